@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jlz22/listly/core"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var addListName string
+var addDone bool
+
+var AddCmd = &cobra.Command{
+	Use:   "add [descriptions...]",
+	Short: "Add tasks to the current or specified list and print their ids. Reads one task per line from stdin if no descriptions are given or the description is \"-\".",
+	Args:  cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		descriptions, err := readDescriptions(args, os.Stdin)
+		if err != nil {
+			return err
+		}
+		if len(descriptions) == 0 {
+			return fmt.Errorf("no tasks to add - pass descriptions as arguments or pipe them in, one per line")
+		}
+
+		return core.WithDefaultDB(func(db *core.DB) error {
+			list, err := getListOrCurrent(db, addListName)
+			if err != nil {
+				return err
+			}
+
+			ids := make([]int, len(descriptions))
+			for i, description := range descriptions {
+				ids[i], err = list.AddNewTask(description, addDone)
+				if err != nil {
+					return err
+				}
+			}
+			if err := db.SaveList(list); err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				fmt.Println(id)
+			}
+			return nil
+		})
+	},
+}
+
+func setUpAdd() {
+	RootCmd.AddCommand(AddCmd)
+	AddCmd.Flags().StringVarP(&addListName, "list", "l", "", "Add the tasks to this list instead of the current one")
+	AddCmd.Flags().BoolVarP(&addDone, "done", "d", false, "Mark the added tasks as done")
+}
+
+// Collect task descriptions from the arguments. A "-" argument, or no arguments
+// at all when stdin is not a terminal, reads one description per line from stdin.
+func readDescriptions(args []string, stdin *os.File) ([]string, error) {
+	if len(args) == 0 {
+		if term.IsTerminal(int(stdin.Fd())) {
+			return nil, nil
+		}
+		args = []string{"-"}
+	}
+
+	descriptions := []string{}
+	for _, arg := range args {
+		if arg != "-" {
+			if strings.TrimSpace(arg) == "" {
+				return nil, fmt.Errorf("task descriptions cannot be empty")
+			}
+			descriptions = append(descriptions, arg)
+			continue
+		}
+		lines, err := readLines(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("could not read tasks from stdin: %v", err)
+		}
+		descriptions = append(descriptions, lines...)
+	}
+	return descriptions, nil
+}
+
+// read the non-blank lines of r with surrounding whitespace removed
+func readLines(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// the given list name, or the name of the current list if it is empty
+func listNameOrCurrent(db *core.DB, listName string) (string, error) {
+	if listName != "" {
+		exists, err := db.ListExists(listName)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return "", fmt.Errorf("list %q does not exist", listName)
+		}
+		return listName, nil
+	}
+	current, err := db.GetCurrentListName()
+	if err != nil {
+		return "", fmt.Errorf("could not retrieve current list name due to the following error\n\t %v", err)
+	}
+	if current == "" {
+		return "", fmt.Errorf("no list selected - pick one with --list or `listly switch <list name>`")
+	}
+	return current, nil
+}
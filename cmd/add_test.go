@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// a file with the given content to read instead of stdin
+func stdinFile(t *testing.T, content string) *os.File {
+	pth := filepath.Join(t.TempDir(), "stdin")
+	require.NoError(t, os.WriteFile(pth, []byte(content), 0644))
+	f, err := os.Open(pth)
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestReadLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"empty", "", []string{}},
+		{"no trailing newline", "a\nb", []string{"a", "b"}},
+		{"trailing newline", "a\nb\n", []string{"a", "b"}},
+		{"CRLF", "a\r\nb\r\n", []string{"a", "b"}},
+		{"blank lines and whitespace", "\n  a  \n\t\n \r\nb c\n\n", []string{"a", "b c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readLines(strings.NewReader(tt.content))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadDescriptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  []string
+	}{
+		{"arguments", []string{"buy milk", " call mom "}, "ignored\n", []string{"buy milk", " call mom "}},
+		{"no arguments reads stdin", nil, "a\n\nb\r\n", []string{"a", "b"}},
+		{"dash reads stdin", []string{"-"}, "a\nb", []string{"a", "b"}},
+		{"dash between arguments", []string{"first", "-", "last"}, "a\n", []string{"first", "a", "last"}},
+		{"empty stdin", []string{"-"}, "\n\n", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readDescriptions(tt.args, stdinFile(t, tt.stdin))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := readDescriptions([]string{"a", "  "}, stdinFile(t, ""))
+	require.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jlz22/listly/core"
+	"github.com/spf13/cobra"
+)
+
+var archiveSince string
+
+var ArchiveCmd = &cobra.Command{
+	Use:   "archive [command]",
+	Short: "Review completed tasks kept by `listly clean --archive`",
+}
+
+var ArchiveShowCmd = &cobra.Command{
+	Use:   "show [list name]",
+	Short: "Print the archived tasks of the specified list or the current list, most recently completed first.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var since time.Time
+		if archiveSince != "" {
+			var err error
+			since, err = core.ParseDate(archiveSince)
+			if err != nil {
+				return err
+			}
+		}
+		listName := ""
+		if len(args) == 1 {
+			listName = args[0]
+		}
+
+		return core.WithDefaultDB(func(db *core.DB) error {
+			listName, err := listNameOrCurrent(db, listName)
+			if err != nil {
+				return err
+			}
+			tasks, err := db.GetArchive(listName, since)
+			if err != nil {
+				return fmt.Errorf("could not retrieve the archive of %s due to the following error\n\t %v", listName, err)
+			}
+
+			fmt.Printf("%s archive (%d tasks)\n", listName, len(tasks))
+			fmt.Println("==========")
+			for _, task := range tasks {
+				fmt.Printf("%-*s  [x] %s\n", len(core.TimestampLayout), formatTimestamp(task.CompletedAt), task.Description)
+			}
+			return nil
+		})
+	},
+}
+
+func setUpArchive() {
+	RootCmd.AddCommand(ArchiveCmd)
+	ArchiveCmd.AddCommand(ArchiveShowCmd)
+	ArchiveShowCmd.Flags().StringVar(&archiveSince, "since", "", "Only show tasks completed on or after this date (YYYY-MM-DD or RFC3339)")
+}
@@ -8,10 +8,11 @@ import (
 )
 
 var cleanAll bool
+var cleanArchive bool
 
 var CleanCmd = &cobra.Command{
 	Use:   "clean [list1 names...]",
-	Short: "Remove all completed tasks from the specified lists or just the current list if none are specified.",
+	Short: "Remove all completed tasks from the specified lists or just the current list if none are specified. Removed tasks go to the trash, or to the archive of their list with --archive.",
 	Args:  cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if cleanAll {
@@ -27,11 +28,12 @@ var CleanCmd = &cobra.Command{
 func setUpClean() {
 	RootCmd.AddCommand(CleanCmd)
 	CleanCmd.Flags().BoolVarP(&cleanAll, "all", "a", false, "Remove all completed tasks from all lists.")
+	CleanCmd.Flags().BoolVar(&cleanArchive, "archive", false, "Keep the completed tasks in the archive of their list, see `listly archive show`")
 }
 
 func cleanAllLists() error {
 	return core.WithDefaultDB(func(db *core.DB) error {
-		numCleaned, err := db.CleanAllLists()
+		numCleaned, err := db.CleanAllLists(cleanArchive)
 		if err != nil {
 			return fmt.Errorf(" cleaning all todo-lists: %v", err)
 		}
@@ -50,7 +52,7 @@ func cleanCurrentList() error {
 			return fmt.Errorf("no current todo-list is set")
 		}
 
-		numCleaned, err := db.CleanCurrentList()
+		numCleaned, err := db.CleanCurrentList(cleanArchive)
 		if err != nil {
 			return fmt.Errorf("could not clean current todo-list due to the following error\n\t %v", err)
 		}
@@ -85,7 +87,7 @@ func cleanSpecifiedLists(names []string) error {
 			}
 		}
 
-		numCleaned, err := db.CleanLists(found)
+		numCleaned, err := db.CleanLists(found, cleanArchive)
 		if err != nil {
 			return fmt.Errorf("could not clean specified todo-lists due to the following error\n\t %v", err)
 		}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CSV and TSV files with a header row. Each row is a task and the list column
+// names the list it belongs to, so one file can hold many lists. Subtasks
+// refer to their parent through the id and parent columns. Headers are matched
+// to the fields below ignoring case, and --columns maps other headers onto them.
+// A list without tasks is written as a row with only the list column filled in.
+
+// the fields of a row, in the order they are exported by default
+var csvFields = []string{"list", "id", "parent", "description", "done", "priority", "tags", "due", "scheduled", "recur", "notes", "created_at", "updated_at", "completed_at"}
+
+type csvColumn struct {
+	header string
+	field  string
+}
+
+// the field delimiter for a .csv or .tsv file
+func csvDelimiter(ext string) rune {
+	if ext == ".tsv" {
+		return '\t'
+	}
+	return ','
+}
+
+// normalize a header or field name so that "Created At" matches created_at
+func normalizeCSVName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+}
+
+// Parse a --columns value such as "Task=description,Status=done,list". Entries
+// without a field use the header as the field name.
+func parseCSVColumns(spec string) ([]csvColumn, error) {
+	var columns []csvColumn
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(spec, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		header, field, ok := strings.Cut(entry, "=")
+		if !ok {
+			field = header
+		}
+		header = strings.TrimSpace(header)
+		field = normalizeCSVName(field)
+		if !slices.Contains(csvFields, field) {
+			return nil, fmt.Errorf("invalid column %q - unknown field %q, expected one of %s", entry, field, strings.Join(csvFields, ", "))
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("invalid columns %q - field %q is used more than once", spec, field)
+		}
+		seen[field] = true
+		columns = append(columns, csvColumn{header: header, field: field})
+	}
+	return columns, nil
+}
+
+// the field of every column of the header row, or "" for columns that are ignored
+func csvHeaderFields(header []string, columns []csvColumn) ([]string, error) {
+	fields := make([]string, len(header))
+	for _, column := range columns {
+		found := false
+		for i, name := range header {
+			if strings.EqualFold(strings.TrimSpace(name), column.header) {
+				fields[i] = column.field
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("column %q of --columns is not in the header row", column.header)
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, name := range header {
+		if fields[i] == "" && slices.Contains(csvFields, normalizeCSVName(name)) {
+			fields[i] = normalizeCSVName(name)
+		}
+		if fields[i] == "" {
+			continue
+		}
+		if seen[fields[i]] {
+			return nil, fmt.Errorf("more than one column holds the %s field", fields[i])
+		}
+		seen[fields[i]] = true
+	}
+	if !seen["description"] {
+		return nil, fmt.Errorf("no description column - name a column description or map one with --columns, e.g. --columns \"Task=description\"")
+	}
+	return fields, nil
+}
+
+func csvToDTOs(content []byte, delimiter rune, columnSpec string) ([]listDTO, error) {
+	columns, err := parseCSVColumns(columnSpec)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	if delimiter == '\t' {
+		reader.LazyQuotes = true
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []listDTO{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fields, err := csvHeaderFields(header, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*flatTask
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		task, err := recordToCSVTask(record, fields)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return flatTasksToDTOs(tasks)
+}
+
+func recordToCSVTask(record []string, fields []string) (*flatTask, error) {
+	task := &flatTask{list: defaultListName}
+	hasList, hasValues := false, false
+	for i, value := range record {
+		if i >= len(fields) {
+			break
+		}
+		// descriptions and notes are kept as they are, other fields are trimmed
+		if fields[i] != "description" && fields[i] != "notes" {
+			value = strings.TrimSpace(value)
+		}
+		if fields[i] != "" && fields[i] != "list" && value != "" {
+			hasValues = true
+		}
+		switch fields[i] {
+		case "list":
+			if value != "" {
+				task.list = value
+				hasList = true
+			}
+		case "id":
+			task.id = value
+		case "parent":
+			task.parent = value
+		case "description":
+			task.dto.Description = value
+		case "done":
+			done, err := parseCSVBool(value)
+			if err != nil {
+				return nil, err
+			}
+			task.dto.Done = done
+		case "priority":
+			task.dto.Priority = value
+		case "tags":
+			if value == "" {
+				break
+			}
+			task.dto.Tags = strings.FieldsFunc(value, func(r rune) bool {
+				return r == ',' || r == ';' || r == ' '
+			})
+		case "due":
+			task.dto.Due = value
+		case "scheduled":
+			task.dto.Scheduled = value
+		case "recur":
+			task.dto.Recur = value
+		case "notes":
+			task.dto.Notes = value
+		case "created_at":
+			task.dto.CreatedAt = value
+		case "updated_at":
+			task.dto.UpdatedAt = value
+		case "completed_at":
+			task.dto.CompletedAt = value
+		}
+	}
+	if hasList && !hasValues {
+		task.emptyList = true
+		return task, nil
+	}
+	if strings.TrimSpace(task.dto.Description) == "" {
+		return nil, fmt.Errorf("task has no description")
+	}
+	return task, nil
+}
+
+// parse the done column, which spreadsheets write in many ways
+func parseCSVBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "no", "n", "pending", "todo", "open":
+		return false, nil
+	case "yes", "y", "x", "done", "completed", "closed":
+		return true, nil
+	}
+	done, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid done value %q - expected e.g. true, false, yes, no or x", value)
+	}
+	return done, nil
+}
+
+func dtosToCSV(dtos []listDTO, delimiter rune, columnSpec string) ([]byte, error) {
+	columns, err := parseCSVColumns(columnSpec)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		for _, field := range csvFields {
+			columns = append(columns, csvColumn{header: field, field: field})
+		}
+	}
+
+	var b bytes.Buffer
+	writer := csv.NewWriter(&b)
+	writer.Comma = delimiter
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.header
+	}
+	writer.Write(header)
+
+	nextId := 1
+	for _, dto := range dtos {
+		if len(dto.Tasks) == 0 {
+			record := make([]string, len(columns))
+			for i, column := range columns {
+				if column.field == "list" {
+					record[i] = dto.Title
+				}
+			}
+			writer.Write(record)
+			continue
+		}
+		writeCSVTasks(writer, columns, dto.Title, dto.Tasks, "", &nextId)
+	}
+	writer.Flush()
+	return b.Bytes(), writer.Error()
+}
+
+func writeCSVTasks(writer *csv.Writer, columns []csvColumn, list string, dtos []taskDTO, parent string, nextId *int) {
+	for _, dto := range dtos {
+		id := strconv.Itoa(*nextId)
+		*nextId++
+		values := map[string]string{
+			"list":         list,
+			"id":           id,
+			"parent":       parent,
+			"description":  dto.Description,
+			"done":         strconv.FormatBool(dto.Done),
+			"priority":     dto.Priority,
+			"tags":         strings.Join(dto.Tags, ","),
+			"due":          dto.Due,
+			"scheduled":    dto.Scheduled,
+			"recur":        dto.Recur,
+			"notes":        dto.Notes,
+			"created_at":   dto.CreatedAt,
+			"updated_at":   dto.UpdatedAt,
+			"completed_at": dto.CompletedAt,
+		}
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = values[column.field]
+		}
+		writer.Write(record)
+		writeCSVTasks(writer, columns, list, dto.Tasks, id, nextId)
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVToDTOs(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		delimiter rune
+		columns   string
+		want      []listDTO
+	}{
+		{
+			name:      "quoted fields",
+			content:   "list,description,done,notes,tags\nwork,\"review, then merge\",yes,\"  line one\n\"\"two\"\"  \",\"a, b\"\n",
+			delimiter: ',',
+			want: []listDTO{{Title: "work", Tasks: []taskDTO{
+				{Description: "review, then merge", Done: true, Notes: "  line one\n\"two\"  ", Tags: []string{"a", "b"}},
+			}}},
+		},
+		{
+			name:      "headers ignoring case, trimmed values and subtasks",
+			content:   "Description,Done, ID ,Parent,Due,Created At\n  padded  , x ,1,,2025-06-20 ,\nsub,false,2, 1 ,,\n",
+			delimiter: ',',
+			want: []listDTO{{Title: defaultListName, Tasks: []taskDTO{
+				{Description: "  padded  ", Done: true, Due: "2025-06-20", Tasks: []taskDTO{{Description: "sub"}}},
+			}}},
+		},
+		{
+			name:      "columns mapping",
+			content:   "Project\tTask Name\tStatus\tIgnored\nhome\tdishes\tdone\tzzz\nwork\tdeploy\topen\t\n",
+			delimiter: '\t',
+			columns:   "Project=list,Task Name=description,Status=done",
+			want: []listDTO{
+				{Title: "home", Tasks: []taskDTO{{Description: "dishes", Done: true}}},
+				{Title: "work", Tasks: []taskDTO{{Description: "deploy"}}},
+			},
+		},
+		{
+			name:      "empty lists and blank rows",
+			content:   "list,description\nempty,\n,\nwork,a\n",
+			delimiter: ',',
+			want: []listDTO{
+				{Title: "empty", Tasks: []taskDTO{}},
+				{Title: "work", Tasks: []taskDTO{{Description: "a"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := csvToDTOs([]byte(tt.content), tt.delimiter, tt.columns)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCSVToDTOs_Errors(t *testing.T) {
+	tests := []struct {
+		content string
+		columns string
+	}{
+		{"list,title\nwork,a\n", ""},                    // no description column
+		{"description,done\na,maybe\n", ""},             // invalid done value
+		{"description,parent\na,7\n", ""},               // missing parent
+		{"list,description\nwork, \n", ""},              // blank description
+		{"Task\na\n", "Task=description,Status=done"},   // mapped column not in the header
+		{"Task\na\n", "Task=description,Other=nothing"}, // unknown field
+		{"description,Desc\na,b\n", "Desc=description"}, // two description columns
+	}
+	for _, tt := range tests {
+		_, err := csvToDTOs([]byte(tt.content), ',', tt.columns)
+		require.Error(t, err, tt.content)
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	dtos := []listDTO{
+		{Title: "work", Tasks: []taskDTO{
+			{
+				Description: " review, \"carefully\" ",
+				Priority:    "high",
+				Tags:        []string{"#backend", "@alice"},
+				Due:         "2025-06-20",
+				Scheduled:   "2025-06-18",
+				Recur:       "weekly on mon,thu",
+				Notes:       "  first\n\tsecond\n",
+				CreatedAt:   "2025-06-01T09:30:00Z",
+				UpdatedAt:   "2025-06-02T09:30:00Z",
+				Tasks: []taskDTO{
+					{Description: "sub", Done: true, CompletedAt: "2025-06-03T10:00:00Z", Tasks: []taskDTO{{Description: "subsub"}}},
+				},
+			},
+		}},
+		{Title: "empty", Tasks: []taskDTO{}},
+		{Title: "home", Tasks: []taskDTO{{Description: "dishes"}}},
+	}
+	for _, delimiter := range []rune{',', '\t'} {
+		content, err := dtosToCSV(dtos, delimiter, "")
+		require.NoError(t, err)
+		got, err := csvToDTOs(content, delimiter, "")
+		require.NoError(t, err)
+		require.Equal(t, dtos, got, string(content))
+	}
+}
+
+func TestDtosToCSV_Columns(t *testing.T) {
+	dtos := []listDTO{{Title: "work", Tasks: []taskDTO{{Description: "a, b", Done: true}}}}
+	content, err := dtosToCSV(dtos, ',', "Task=description,Finished=done")
+	require.NoError(t, err)
+	require.Equal(t, "Task,Finished\n\"a, b\",true\n", string(content))
+
+	got, err := csvToDTOs(content, ',', "Task=description,Finished=done")
+	require.NoError(t, err)
+	require.Equal(t, []listDTO{{Title: defaultListName, Tasks: dtos[0].Tasks}}, got)
+}
@@ -11,7 +11,7 @@ var deleteAll bool
 
 var DeleteCmd = &cobra.Command{
 	Use:   "delete <list name> [more list names...]",
-	Short: "Delete the specified list(s).",
+	Short: "Move the specified list(s) to the trash. Bring them back with `listly trash restore <list name>`.",
 	Args:  cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if deleteAll {
@@ -26,7 +26,7 @@ var DeleteCmd = &cobra.Command{
 
 func setUpDelete() {
 	RootCmd.AddCommand(DeleteCmd)
-	DeleteCmd.Flags().BoolVarP(&deleteAll, "all", "a", false, "Delete all lists")
+	DeleteCmd.Flags().BoolVarP(&deleteAll, "all", "a", false, "Move all lists to the trash")
 }
 
 func deleteAllLists() error {
@@ -35,7 +35,7 @@ func deleteAllLists() error {
 		if err != nil {
 			return fmt.Errorf("could not delete all todo-lists due to the following error\n\t %v", err)
 		}
-		core.Success("Deleted all todo-lists.")
+		core.Success("Moved all todo-lists to the trash.")
 		return nil
 	})
 }
@@ -71,7 +71,7 @@ func deleteSpecifiedLists(names []string) error {
 			return fmt.Errorf("could not delete specified todo-lists due to the following error\n\t %v", err)
 		}
 
-		core.Success(fmt.Sprintf("Deleted the following:\n%s", core.ListLists(found, "  ")))
+		core.Success(fmt.Sprintf("Moved the following to the trash:\n%s", core.ListLists(found, "  ")))
 		if len(notFound) > 0 {
 			fmt.Printf("Could not find the following:\n%s", core.ListLists(notFound, "  "))
 		}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jlz22/listly/core"
+	"github.com/spf13/cobra"
+)
+
+var doneListName string
+var doneById bool
+
+var DoneCmd = &cobra.Command{
+	Use:   "done <task> [other tasks...]",
+	Short: "Mark tasks as done. Tasks are given by their position in `listly show -n` or by id with --id.",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCompletion(args, doneListName, doneById, true)
+	},
+}
+
+var undoneListName string
+var undoneById bool
+
+var UndoneCmd = &cobra.Command{
+	Use:   "undone <task> [other tasks...]",
+	Short: "Mark tasks as not done. Tasks are given by their position in `listly show -n` or by id with --id.",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCompletion(args, undoneListName, undoneById, false)
+	},
+}
+
+func setUpDone() {
+	RootCmd.AddCommand(DoneCmd)
+	DoneCmd.Flags().StringVarP(&doneListName, "list", "l", "", "Use this list instead of the current one")
+	DoneCmd.Flags().BoolVarP(&doneById, "id", "i", false, "Treat the arguments as task ids instead of positions")
+
+	RootCmd.AddCommand(UndoneCmd)
+	UndoneCmd.Flags().StringVarP(&undoneListName, "list", "l", "", "Use this list instead of the current one")
+	UndoneCmd.Flags().BoolVarP(&undoneById, "id", "i", false, "Treat the arguments as task ids instead of positions")
+}
+
+// mark the given tasks of the list as done or not done and save the list
+func setCompletion(args []string, listName string, byId bool, done bool) error {
+	return core.WithDefaultDB(func(db *core.DB) error {
+		list, err := getListOrCurrent(db, listName)
+		if err != nil {
+			return err
+		}
+		ids, err := resolveTaskIds(list, args, byId)
+		if err != nil {
+			return err
+		}
+
+		changed := []string{}
+		for _, id := range ids {
+			task := list.Tasks[id]
+			if task.Done == done {
+				continue // already done, possibly because its parent was given as well
+			}
+			if err := list.ToggleCompletion(id); err != nil {
+				return err
+			}
+			changed = append(changed, task.Description)
+		}
+		if err := db.SaveList(list); err != nil {
+			return err
+		}
+
+		status := "done"
+		if !done {
+			status = "not done"
+		}
+		if len(changed) == 0 {
+			fmt.Printf("All given tasks were already marked as %s.\n", status)
+			return nil
+		}
+		fmt.Printf("Marked the following tasks in %q as %s:\n%s\n", list.Info.Name, status, core.ListLists(changed, "  "))
+		return nil
+	})
+}
+
+// the given list, or the current list if listName is empty
+func getListOrCurrent(db *core.DB, listName string) (core.List, error) {
+	listName, err := listNameOrCurrent(db, listName)
+	if err != nil {
+		return core.List{}, err
+	}
+	list, err := db.GetList(listName)
+	if err != nil {
+		return core.List{}, fmt.Errorf("could not retrieve list %s due to the following error\n\t %v", listName, err)
+	}
+	return list, nil
+}
+
+// Convert task positions (see core.List.TaskAtPosition) or task ids into task ids.
+// All arguments are resolved before anything changes, since changing a task can
+// change the positions of the others.
+func resolveTaskIds(list core.List, args []string, byId bool) ([]int, error) {
+	ids := []int{}
+	seen := map[int]struct{}{}
+	for _, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid task %q - expected a number", arg)
+		}
+
+		var id int
+		if byId {
+			if _, ok := list.Tasks[n]; !ok {
+				return nil, fmt.Errorf("no task with id %d in list %s", n, list.Info.Name)
+			}
+			id = n
+		} else {
+			task, err := list.TaskAtPosition(n)
+			if err != nil {
+				return nil, err
+			}
+			id = task.Id
+		}
+
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jlz22/listly/core"
+	"github.com/spf13/cobra"
+)
+
+var editListName string
+var editById bool
+
+var EditCmd = &cobra.Command{
+	Use:   "edit <task> <new description>",
+	Short: "Change the description of a task. The task is given by its position in `listly show -n` or by id with --id.",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		description := strings.TrimSpace(args[1])
+		if description == "" {
+			return fmt.Errorf("task descriptions cannot be empty")
+		}
+
+		return core.WithDefaultDB(func(db *core.DB) error {
+			list, err := getListOrCurrent(db, editListName)
+			if err != nil {
+				return err
+			}
+			ids, err := resolveTaskIds(list, args[:1], editById)
+			if err != nil {
+				return err
+			}
+
+			old := list.Tasks[ids[0]].Description
+			if err := list.EditTaskDescription(ids[0], description); err != nil {
+				return err
+			}
+			if err := db.SaveList(list); err != nil {
+				return err
+			}
+
+			fmt.Printf("Changed %q to %q in %q.\n", old, description, list.Info.Name)
+			return nil
+		})
+	},
+}
+
+func setUpEdit() {
+	RootCmd.AddCommand(EditCmd)
+	EditCmd.Flags().StringVarP(&editListName, "list", "l", "", "Use this list instead of the current one")
+	EditCmd.Flags().BoolVarP(&editById, "id", "i", false, "Treat the first argument as a task id instead of a position")
+}
@@ -4,20 +4,46 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/jlz22/listly/core"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+var exportFilter string
+var exportColumns string
+var exportTaskwarrior bool
+var exportIds bool
+var exportMetadata bool
+var exportFormat string
+
 var ExportCmd = &cobra.Command{
 	Use:   "export <file> [list names...] ",
-	Short: "Export list to a file. Uses current list if no list name(s) provided. Supported formats: JSON, YAML",
+	Short: "Export list to a file. Uses current list if no list name(s) provided. Supported formats: JSON, YAML, Markdown, todo.txt, CSV, TSV, iCalendar, Org",
+	Long:  "Export lists to a file, or to stdout if the file is -. Exports the current list if no list names are given. The format is picked from the file extension unless --format is given.",
 	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ext, err := formatExt(args[0], exportFormat)
+		if err != nil {
+			return err
+		}
+		if strings.ToLower(exportFormat) == "taskwarrior" {
+			exportTaskwarrior = true
+		}
+
+		var filter *core.Filter
+		if exportFilter != "" {
+			var err error
+			filter, err = core.ParseFilter(exportFilter, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
 		lists := make([]core.List, max(1, len(args)-1))
-		err := core.WithDefaultDB(func(db *core.DB) error {
+		err = core.WithDefaultDB(func(db *core.DB) error {
 			var fileName string
 
 			if len(args) == 1 { // no list name specified so use the current list
@@ -42,15 +68,32 @@ var ExportCmd = &cobra.Command{
 				}
 			}
 
-			content, err := dataToFile(lists, filepath.Ext(fileName))
+			if filter != nil {
+				for i := range lists {
+					lists[i] = lists[i].Filtered(filter)
+				}
+			}
+
+			currentList, err := db.GetCurrentListName()
+			if err != nil {
+				return err
+			}
+			content, err := dataToFile(lists, ext, currentList)
 			if err != nil {
 				return err
 			}
+			if fileName == stdioFileName {
+				_, err = os.Stdout.Write(content)
+				return err
+			}
 			return os.WriteFile(fileName, content, 0644)
 		})
 		if err != nil {
 			return err
 		}
+		if args[0] == stdioFileName { // keep stdout to the exported content
+			return nil
+		}
 		fmt.Printf("Exported the following lists to \"%s\":\n", args[0])
 		for _, list := range lists {
 			fmt.Println("  - ", list.Info.Name)
@@ -61,9 +104,17 @@ var ExportCmd = &cobra.Command{
 
 func setUpExport() {
 	RootCmd.AddCommand(ExportCmd)
+	ExportCmd.Flags().StringVarP(&exportFilter, "filter", "f", "", "Only export tasks matching a filter, e.g. 'pending and tag:backend' (see the README)")
+	ExportCmd.Flags().StringVar(&exportFormat, "format", "", "The format to write, overriding the file extension: json, yaml, markdown, todotxt, csv, tsv, ical, org or taskwarrior. Required for stdout")
+	ExportCmd.Flags().BoolVar(&exportIds, "ids", false, "Include task ids in JSON and YAML files so that importing restores the same ids")
+	ExportCmd.Flags().BoolVar(&exportMetadata, "metadata", false, "Include the task counts of each list and whether it is the current list in JSON and YAML files")
+	ExportCmd.Flags().BoolVar(&exportTaskwarrior, "taskwarrior", false, "Write Taskwarrior JSON for \"task import\" instead of listly JSON")
+	ExportCmd.Flags().StringVar(&exportColumns, "columns", "", "The CSV/TSV columns to write and their headers, e.g. \"Project=list,Task=description,Status=done\"")
 }
 
-func dataToFile(lists []core.List, ext string) ([]byte, error) {
+// Convert the lists into the format of the extension. The name of the current
+// list is only used for the metadata written with --metadata.
+func dataToFile(lists []core.List, ext string, currentList string) ([]byte, error) {
 	var content []byte
 	var err error
 	var dtos []listDTO = make([]listDTO, len(lists))
@@ -72,26 +123,85 @@ func dataToFile(lists []core.List, ext string) ([]byte, error) {
 	for i, list := range lists {
 		var dto listDTO
 		dto.Title = list.Info.Name
-		for _, task := range list.Tasks {
-			dto.Tasks = append(dto.Tasks, taskDTO{
-				Description: task.Description,
-				Done:        task.Done,
-			})
+		dto.CreatedAt = formatDTOTimestamp(list.Info.CreatedAt)
+		dto.UpdatedAt = formatDTOTimestamp(list.Info.UpdatedAt)
+		dto.Tasks = tasksToDTOs(list, list.ChildIds(0))
+		if exportIds {
+			dto.NextId = list.Info.NextId
+		}
+		if exportMetadata {
+			dto.Metadata = &listMetadataDTO{
+				Current: list.Info.Name == currentList,
+				Tasks:   list.Info.NumTasks,
+				Done:    list.Info.NumDone,
+				Pending: list.Info.NumPending,
+			}
 		}
 		dtos[i] = dto
 	}
 
+	if exportTaskwarrior && ext != ".json" {
+		return content, fmt.Errorf("--taskwarrior can only be used with JSON")
+	}
+
 	// marshal every DTO based on file extension
 	switch ext {
 	case ".json":
+		if exportTaskwarrior {
+			content, err = dtosToTaskwarrior(dtos, time.Now())
+			break
+		}
 		content, err = json.MarshalIndent(dtos, "", "  ")
 	case ".yaml":
 		content, err = yaml.Marshal(dtos)
+	case ".md":
+		content = dtosToMarkdown(dtos)
+	case ".txt":
+		content, err = dtosToTodoTxt(dtos)
+	case ".csv", ".tsv":
+		content, err = dtosToCSV(dtos, csvDelimiter(ext), exportColumns)
+	case ".ics":
+		content, err = dtosToICS(dtos, time.Now())
+	case ".org":
+		content, err = dtosToOrg(dtos)
 	default:
-		return content, fmt.Errorf("unsupported file format: \"%s\". Supported formats are JSON and YAML", ext)
+		return content, fmt.Errorf("unsupported file format: \"%s\". Supported formats are JSON, YAML, Markdown, todo.txt, CSV, TSV, iCalendar and Org", ext)
 	}
 	if err != nil {
 		return content, err
 	}
 	return content, nil
 }
+
+// convert the tasks with the given ids (and their subtasks) into dtos, keeping their order
+func tasksToDTOs(list core.List, ids []int) []taskDTO {
+	var dtos []taskDTO
+	for _, id := range ids {
+		dto := taskToDTO(list.Tasks[id])
+		if exportIds {
+			dto.Id = id
+			dto.NextId = list.Tasks[id].NextId
+		}
+		dto.Tasks = tasksToDTOs(list, list.ChildIds(id))
+		dtos = append(dtos, dto)
+	}
+	return dtos
+}
+
+// convert a single task into a dto without its subtasks
+func taskToDTO(task *core.Task) taskDTO {
+	return taskDTO{
+		Description: task.Description,
+		Done:        task.Done,
+		Priority:    formatDTOPriority(task.Priority),
+		Tags:        task.Tags,
+		Due:         formatDTODate(task.Due),
+		Scheduled:   formatDTODate(task.Scheduled),
+		Recur:       task.Recurrence.String(),
+		Notes:       task.Notes,
+		CreatedAt:   formatDTOTimestamp(task.CreatedAt),
+		UpdatedAt:   formatDTOTimestamp(task.UpdatedAt),
+		CompletedAt: formatDTOTimestamp(task.CompletedAt),
+		UUID:        task.UUID,
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// the file name that reads from stdin or writes to stdout
+const stdioFileName = "-"
+
+// names accepted by --format and the extensions of their formats
+var formatExts = map[string]string{
+	"json":        ".json",
+	"yaml":        ".yaml",
+	"yml":         ".yaml",
+	"markdown":    ".md",
+	"md":          ".md",
+	"todotxt":     ".txt",
+	"txt":         ".txt",
+	"csv":         ".csv",
+	"tsv":         ".tsv",
+	"ical":        ".ics",
+	"ics":         ".ics",
+	"org":         ".org",
+	"taskwarrior": ".json",
+}
+
+// Pick the extension that decides the format of a file, from --format if it
+// is given and from the file name otherwise. Extensions such as .yml and .TXT
+// are treated like the extensions of their formats.
+func formatExt(fileName, format string) (string, error) {
+	if format == "" {
+		if fileName == stdioFileName {
+			return "", fmt.Errorf("--format is required when using - for stdin or stdout")
+		}
+		ext := strings.ToLower(filepath.Ext(fileName))
+		if known, ok := formatExts[strings.TrimPrefix(ext, ".")]; ok && ext != "" {
+			return known, nil
+		}
+		return ext, nil
+	}
+	ext, ok := formatExts[strings.ToLower(format)]
+	if !ok {
+		return "", fmt.Errorf("unsupported format %q - expected json, yaml, markdown, todotxt, csv, tsv, ical, org or taskwarrior", format)
+	}
+	return ext, nil
+}
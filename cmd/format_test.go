@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatExt(t *testing.T) {
+	tests := []struct {
+		fileName string
+		format   string
+		want     string
+	}{
+		{"tasks.json", "", ".json"},
+		{"tasks.yml", "", ".yaml"},
+		{"tasks.YAML", "", ".yaml"},
+		{"tasks.tsv", "", ".tsv"},
+		{"todo.txt", "", ".txt"},
+		{"calendar.ics", "", ".ics"},
+		{"tasks.pdf", "", ".pdf"}, // rejected when the file is read or written
+		{"tasks", "", ""},
+		{"tasks.csv", "json", ".json"}, // --format wins over the extension
+		{"tasks.txt", "TSV", ".tsv"},
+		{"tasks.json", "yml", ".yaml"},
+		{"tasks.json", "taskwarrior", ".json"},
+		{"-", "markdown", ".md"},
+		{"-", "ical", ".ics"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName+" "+tt.format, func(t *testing.T) {
+			got, err := formatExt(tt.fileName, tt.format)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatExt_Errors(t *testing.T) {
+	tests := []struct {
+		fileName string
+		format   string
+	}{
+		{"-", ""},           // stdin or stdout needs --format
+		{"-", "pdf"},        // unknown format
+		{"tasks.json", "x"}, // unknown format, even with a known extension
+		{"tasks.json", ".json"},
+	}
+	for _, tt := range tests {
+		_, err := formatExt(tt.fileName, tt.format)
+		require.Error(t, err, tt.fileName+" "+tt.format)
+	}
+}
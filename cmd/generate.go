@@ -16,7 +16,8 @@ import (
 var instructions = "You are an experienced developer who needs to make a " +
 	"set of todo lists for your junior developer so that they can complete whatever is " +
 	"described below. Each list should break the tasks into clear steps as a developer " +
-	"roadmap. Use UpperCamelCase for list titles."
+	"roadmap, breaking larger steps into sub-steps with nested tasks where it helps. " +
+	"Use UpperCamelCase for list titles."
 
 var timeoutFlagValue int
 
@@ -65,7 +66,7 @@ var GenerateCmd = &cobra.Command{
 			}
 
 			// convert Gemini output to List type
-			lists, err := fileToData([]byte(result.Text()), ".json")
+			lists, _, err := fileToData([]byte(result.Text()), ".json")
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,435 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jlz22/listly/core"
+)
+
+// iCalendar files (RFC 5545) holding a VTODO for every task. The first of its
+// CATEGORIES is the list a task belongs to and the others are its tags.
+// The UID of a VTODO is the UUID of its task, so calendar apps recognize tasks
+// exported again. Subtasks point at the UID of their parent with RELATED-TO.
+// Other components, such as events, are ignored when importing.
+
+const icsDateLayout = "20060102"
+const icsUTCLayout = "20060102T150405Z"
+const icsLocalLayout = "20060102T150405"
+
+// the PRIORITY written for each listly priority. Higher values mean lower priorities.
+var icsPriorities = map[string]int{"urgent": 1, "high": 3, "medium": 5, "low": 9}
+
+var icsWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// a content line such as DUE;VALUE=DATE:20250620
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func icsToDTOs(content []byte) ([]listDTO, error) {
+	properties, err := parseICSProperties(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*flatTask
+	var task *flatTask
+	var components []string // the components the current line is nested in
+	for _, property := range properties {
+		switch property.name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(property.value))
+			if components[len(components)-1] == "VTODO" {
+				task = &flatTask{}
+			}
+			continue
+		case "END":
+			if len(components) == 0 || components[len(components)-1] != strings.ToUpper(property.value) {
+				return nil, fmt.Errorf("unexpected END:%s", property.value)
+			}
+			components = components[:len(components)-1]
+			if strings.ToUpper(property.value) == "VTODO" {
+				if task.dto.Description == "" {
+					return nil, fmt.Errorf("VTODO %q has no SUMMARY", task.id)
+				}
+				if task.list == "" {
+					task.list = defaultListName
+				}
+				tasks = append(tasks, task)
+				task = nil
+			}
+			continue
+		}
+		if len(components) == 0 || components[len(components)-1] != "VTODO" {
+			continue
+		}
+		err := setICSProperty(task, property)
+		if err != nil {
+			return nil, fmt.Errorf("VTODO %q: %v", task.dto.Description, err)
+		}
+	}
+	if len(components) > 0 {
+		return nil, fmt.Errorf("missing END:%s", components[len(components)-1])
+	}
+
+	// calendars often hold only some of the tasks, so subtasks of missing tasks become top-level tasks
+	uids := map[string]bool{}
+	for _, task := range tasks {
+		uids[task.id] = true
+	}
+	for _, task := range tasks {
+		if !uids[task.parent] {
+			task.parent = ""
+		}
+	}
+	return flatTasksToDTOs(tasks)
+}
+
+// unfold the content lines of an iCalendar file and split them into properties
+func parseICSProperties(content string) ([]icsProperty, error) {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var properties []icsProperty
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		// the value starts at the first colon that is not inside a quoted parameter
+		colon := -1
+		quoted := false
+		for j, r := range line {
+			if r == '"' {
+				quoted = !quoted
+			} else if r == ':' && !quoted {
+				colon = j
+				break
+			}
+		}
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: expected NAME:value but got %q", i+1, line)
+		}
+
+		parts := strings.Split(line[:colon], ";")
+		property := icsProperty{name: strings.ToUpper(parts[0]), params: map[string]string{}, value: line[colon+1:]}
+		for _, param := range parts[1:] {
+			key, value, _ := strings.Cut(param, "=")
+			property.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+		properties = append(properties, property)
+	}
+	return properties, nil
+}
+
+func setICSProperty(task *flatTask, property icsProperty) error {
+	var err error
+	switch property.name {
+	case "UID":
+		task.id = property.value
+		task.dto.UUID = property.value
+	case "SUMMARY":
+		task.dto.Description = unescapeICSText(property.value)
+	case "DESCRIPTION":
+		task.dto.Notes = unescapeICSText(property.value)
+	case "STATUS":
+		task.dto.Done = strings.EqualFold(property.value, "COMPLETED")
+	case "COMPLETED":
+		task.dto.Done = true
+		task.dto.CompletedAt, err = parseICSTime(property, false)
+	case "CREATED":
+		task.dto.CreatedAt, err = parseICSTime(property, false)
+	case "LAST-MODIFIED":
+		task.dto.UpdatedAt, err = parseICSTime(property, false)
+	case "DUE":
+		task.dto.Due, err = parseICSTime(property, true)
+	case "DTSTART":
+		task.dto.Scheduled, err = parseICSTime(property, true)
+	case "PRIORITY":
+		task.dto.Priority, err = parseICSPriority(property.value)
+	case "CATEGORIES":
+		for _, category := range splitICSList(property.value) {
+			if task.list == "" {
+				task.list = category
+			} else {
+				task.dto.Tags = append(task.dto.Tags, category)
+			}
+		}
+	case "RRULE":
+		task.dto.Recur = parseICSRecurrence(property.value)
+	case "RELATED-TO":
+		reltype := strings.ToUpper(property.params["RELTYPE"])
+		if reltype == "" || reltype == "PARENT" {
+			task.parent = property.value
+		}
+	}
+	return err
+}
+
+// Parse a date or date-time into a dto date or timestamp. Dates without a time
+// are kept as dates if allowDate is set and are midnight local time otherwise.
+func parseICSTime(property icsProperty, allowDate bool) (string, error) {
+	value := property.value
+	if property.params["VALUE"] == "DATE" || len(value) == len(icsDateLayout) {
+		t, err := time.ParseInLocation(icsDateLayout, value, time.Local)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s %q - expected e.g. 20250620", property.name, value)
+		}
+		if allowDate {
+			return core.FormatDate(t), nil
+		}
+		return t.Format(time.RFC3339), nil
+	}
+
+	location := time.Local
+	if tzid := property.params["TZID"]; tzid != "" {
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			location = loc
+		}
+	}
+	t, err := time.Parse(icsUTCLayout, value)
+	if err != nil {
+		t, err = time.ParseInLocation(icsLocalLayout, value, location)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid %s %q - expected e.g. 20250620T093000Z", property.name, value)
+	}
+	return t.Format(time.RFC3339), nil
+}
+
+// convert a PRIORITY from 1 (highest) to 9 (lowest) into a listly priority. 0 means none.
+func parseICSPriority(value string) (string, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > 9 {
+		return "", fmt.Errorf("invalid PRIORITY %q - expected a number from 0 to 9", value)
+	}
+	switch {
+	case n == 0:
+		return "", nil
+	case n <= 2:
+		return "urgent", nil
+	case n <= 4:
+		return "high", nil
+	case n == 5:
+		return "medium", nil
+	default:
+		return "low", nil
+	}
+}
+
+// Convert an RRULE into a listly recurrence. Rules listly cannot express, such
+// as yearly ones or rules ending after a number of occurrences, are left out.
+func parseICSRecurrence(value string) string {
+	r := core.Recurrence{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Frequency = map[string]core.Frequency{"DAILY": core.FrequencyDaily, "WEEKLY": core.FrequencyWeekly, "MONTHLY": core.FrequencyMonthly}[strings.ToUpper(value)]
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return ""
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(value), ",") {
+				i := slices.Index(icsWeekdays, day)
+				if i < 0 {
+					return "" // e.g. 1MO, the first monday of the month
+				}
+				r.Weekdays = append(r.Weekdays, time.Weekday(i))
+			}
+		case "WKST": // the first day of the week does not change when tasks repeat
+		default:
+			return ""
+		}
+	}
+	if r.Frequency != core.FrequencyWeekly && len(r.Weekdays) > 0 {
+		return ""
+	}
+	// round trip through the parser to order the weekdays
+	parsed, err := core.ParseRecurrence(r.String())
+	if err != nil {
+		return ""
+	}
+	return parsed.String()
+}
+
+func formatICSRecurrence(recur string) string {
+	r, err := core.ParseRecurrence(recur)
+	if err != nil || !r.IsSet() {
+		return ""
+	}
+	freq := map[core.Frequency]string{core.FrequencyDaily: "DAILY", core.FrequencyWeekly: "WEEKLY", core.FrequencyMonthly: "MONTHLY"}[r.Frequency]
+	out := "FREQ=" + freq
+	if r.Interval > 1 {
+		out += fmt.Sprintf(";INTERVAL=%d", r.Interval)
+	}
+	if len(r.Weekdays) > 0 {
+		days := make([]string, len(r.Weekdays))
+		for i, day := range r.Weekdays {
+			days[i] = icsWeekdays[day]
+		}
+		out += ";BYDAY=" + strings.Join(days, ",")
+	}
+	return out
+}
+
+// split a comma separated value, leaving escaped commas alone
+func splitICSList(value string) []string {
+	var values []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			current.WriteRune('\\')
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			values = append(values, unescapeICSText(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	values = append(values, unescapeICSText(current.String()))
+
+	nonEmpty := []string{}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+	return nonEmpty
+}
+
+var icsUnescaper = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";")
+var icsEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`)
+
+func unescapeICSText(value string) string {
+	return icsUnescaper.Replace(value)
+}
+
+func escapeICSText(value string) string {
+	return icsEscaper.Replace(value)
+}
+
+func dtosToICS(dtos []listDTO, now time.Time) ([]byte, error) {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//listly//listly//EN")
+	for _, dto := range dtos {
+		err := writeICSTasks(&b, dto.Title, dto.Tasks, "", now)
+		if err != nil {
+			return nil, fmt.Errorf("list %q: %v", dto.Title, err)
+		}
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+	return []byte(b.String()), nil
+}
+
+func writeICSTasks(b *strings.Builder, list string, dtos []taskDTO, parent string, now time.Time) error {
+	for _, dto := range dtos {
+		uid := dto.UUID
+		if uid == "" {
+			uid = core.NewUUID()
+		}
+
+		writeICSLine(b, "BEGIN:VTODO")
+		writeICSLine(b, "UID:"+uid)
+		writeICSLine(b, "DTSTAMP:"+now.UTC().Format(icsUTCLayout))
+		writeICSLine(b, "SUMMARY:"+escapeICSText(dto.Description))
+		if dto.Done {
+			writeICSLine(b, "STATUS:COMPLETED")
+		} else {
+			writeICSLine(b, "STATUS:NEEDS-ACTION")
+		}
+		if priority, ok := icsPriorities[dto.Priority]; ok {
+			writeICSLine(b, fmt.Sprintf("PRIORITY:%d", priority))
+		}
+
+		categories := []string{escapeICSText(list)}
+		for _, tag := range dto.Tags {
+			categories = append(categories, escapeICSText(strings.TrimPrefix(core.NormalizeTag(tag), "#")))
+		}
+		writeICSLine(b, "CATEGORIES:"+strings.Join(categories, ","))
+
+		for _, field := range []struct{ name, value string }{
+			{"DUE", dto.Due},
+			{"DTSTART", dto.Scheduled},
+			{"CREATED", dto.CreatedAt},
+			{"LAST-MODIFIED", dto.UpdatedAt},
+			{"COMPLETED", dto.CompletedAt},
+		} {
+			line, err := formatICSTime(field.name, field.value)
+			if err != nil {
+				return fmt.Errorf("task %q: %v", dto.Description, err)
+			}
+			if line != "" {
+				writeICSLine(b, line)
+			}
+		}
+
+		if recur := formatICSRecurrence(dto.Recur); recur != "" {
+			writeICSLine(b, "RRULE:"+recur)
+		}
+		if dto.Notes != "" {
+			writeICSLine(b, "DESCRIPTION:"+escapeICSText(dto.Notes))
+		}
+		if parent != "" {
+			writeICSLine(b, "RELATED-TO;RELTYPE=PARENT:"+parent)
+		}
+		writeICSLine(b, "END:VTODO")
+
+		err := writeICSTasks(b, list, dto.Tasks, uid, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// format a dto date or timestamp as a property, using a plain date for dates without a time
+func formatICSTime(name, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	t, err := core.ParseDate(value)
+	if err != nil {
+		return "", err
+	}
+	if len(value) == len(core.DateLayout) {
+		return name + ";VALUE=DATE:" + t.Format(icsDateLayout), nil
+	}
+	return name + ":" + t.UTC().Format(icsUTCLayout), nil
+}
+
+// write a content line, folding it into lines of at most 75 bytes
+func writeICSLine(b *strings.Builder, line string) {
+	for len(line) > 75 {
+		cut := 75
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n")
+		line = " " + line[cut:]
+	}
+	b.WriteString(line + "\r\n")
+}
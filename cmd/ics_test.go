@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestICSToDTOs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []listDTO
+	}{
+		{
+			name: "properties",
+			content: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:a@example.com\r\nSUMMARY:call the plumber\r\n" +
+				"STATUS:COMPLETED\r\nCOMPLETED:20250603T100000Z\r\nPRIORITY:2\r\nCATEGORIES:house,Phone\r\n" +
+				"DUE;VALUE=DATE:20250620\r\nDTSTART:20250618\r\nCREATED:20250601T093000Z\r\n" +
+				"RRULE:FREQ=WEEKLY;BYDAY=TH,MO;WKST=MO\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			want: []listDTO{{Title: "house", Tasks: []taskDTO{{
+				Description: "call the plumber",
+				Done:        true,
+				Priority:    "urgent",
+				Tags:        []string{"Phone"},
+				Due:         "2025-06-20",
+				Scheduled:   "2025-06-18",
+				Recur:       "weekly on mon,thu",
+				CreatedAt:   "2025-06-01T09:30:00Z",
+				CompletedAt: "2025-06-03T10:00:00Z",
+				UUID:        "a@example.com",
+			}}}},
+		},
+		{
+			name: "folded lines and escaped text",
+			content: "BEGIN:VCALENDAR\nBEGIN:VTODO\nUID:b\nSUMMARY:review\\, then\n  merge\\; deploy\n" +
+				"DESCRIPTION:first\\nsecond\\N\\\\third\n\tfourth\nCATEGORIES:work\\, team,,\n #backend\nEND:VTODO\nEND:VCALENDAR\n",
+			want: []listDTO{{Title: "work, team", Tasks: []taskDTO{{
+				Description: "review, then merge; deploy",
+				Notes:       "first\nsecond\n\\thirdfourth",
+				Tags:        []string{"#backend"},
+				UUID:        "b",
+			}}}},
+		},
+		{
+			name: "subtasks, events and missing parents",
+			content: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:e\nSUMMARY:meeting\nEND:VEVENT\n" +
+				"BEGIN:VTODO\nUID:c\nSUMMARY:sub\nRELATED-TO:p\nEND:VTODO\n" +
+				"BEGIN:VTODO\nUID:p\nSUMMARY:parent\nRRULE:FREQ=YEARLY\nEND:VTODO\n" +
+				"BEGIN:VTODO\nUID:o\nSUMMARY:orphan\nRELATED-TO;RELTYPE=PARENT:gone\nRELATED-TO;RELTYPE=SIBLING:c\nEND:VTODO\n" +
+				"END:VCALENDAR\n",
+			want: []listDTO{{Title: defaultListName, Tasks: []taskDTO{
+				{Description: "parent", UUID: "p", Tasks: []taskDTO{{Description: "sub", UUID: "c"}}},
+				{Description: "orphan", UUID: "o"},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := icsToDTOs([]byte(tt.content))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestICSToDTOs_Errors(t *testing.T) {
+	for _, content := range []string{
+		"BEGIN:VCALENDAR\nBEGIN:VTODO\nUID:a\nEND:VTODO\nEND:VCALENDAR\n",                   // no SUMMARY
+		"BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY:a\nEND:VCALENDAR\n",                          // mismatched END
+		"BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY:a\nEND:VTODO\n",                              // missing END
+		"BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY:a\nPRIORITY:10\nEND:VTODO\nEND:VCALENDAR\n",  // invalid priority
+		"BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY:a\nDUE:tomorrow\nEND:VTODO\nEND:VCALENDAR\n", // invalid date
+		"BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY a\nEND:VTODO\nEND:VCALENDAR\n",               // no colon
+	} {
+		_, err := icsToDTOs([]byte(content))
+		require.Error(t, err, content)
+	}
+}
+
+func TestWriteICSLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"short", "SUMMARY:a", "SUMMARY:a\r\n"},
+		{"exactly 75 bytes", strings.Repeat("x", 75), strings.Repeat("x", 75) + "\r\n"},
+		{"folded", strings.Repeat("x", 160), strings.Repeat("x", 75) + "\r\n " + strings.Repeat("x", 74) + "\r\n " + strings.Repeat("x", 11) + "\r\n"},
+		{"multibyte characters stay whole", strings.Repeat("x", 74) + "é", strings.Repeat("x", 74) + "\r\n é\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			writeICSLine(&b, tt.line)
+			require.Equal(t, tt.want, b.String())
+			for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+				require.LessOrEqual(t, len(line), 75)
+			}
+		})
+	}
+}
+
+func TestICSText(t *testing.T) {
+	tests := []struct{ text, escaped string }{
+		{"plain", "plain"},
+		{"a, b; c", `a\, b\; c`},
+		{"line one\nline two", `line one\nline two`},
+		{`C:\temp\new`, `C:\\temp\\new`},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.escaped, escapeICSText(tt.text))
+		require.Equal(t, tt.text, unescapeICSText(tt.escaped))
+	}
+}
+
+func TestICSRoundTrip(t *testing.T) {
+	dtos := []listDTO{
+		{Title: "work; team", Tasks: []taskDTO{
+			{
+				Description: "review, " + strings.Repeat("then merge ", 10) + "and deploy",
+				Priority:    "high",
+				Tags:        []string{"backend", "@alice"},
+				Due:         "2025-06-20",
+				Scheduled:   "2025-06-18",
+				Recur:       "weekly on mon,thu",
+				Notes:       "first\n\nsecond, with a \\ and ü",
+				CreatedAt:   "2025-06-01T09:30:00Z",
+				UpdatedAt:   "2025-06-02T09:30:00Z",
+				UUID:        "0b6c9e1a-34f2-4c55-9d0e-2f1a7b8c9d01",
+				Tasks: []taskDTO{
+					{Description: "sub", Done: true, CompletedAt: "2025-06-03T10:00:00Z", UUID: "sub@example.com", Tasks: []taskDTO{
+						{Description: "subsub", UUID: "5e2f8a4b-1c3d-4e5f-8a9b-0c1d2e3f4a5b"},
+					}},
+				},
+			},
+		}},
+		{Title: "home", Tasks: []taskDTO{{Description: "dishes", Priority: "low", UUID: "7d8e9f0a-1b2c-4d3e-8f4a-5b6c7d8e9f0a"}}},
+	}
+	now := time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC)
+	content, err := dtosToICS(dtos, now)
+	require.NoError(t, err)
+	got, err := icsToDTOs(content)
+	require.NoError(t, err)
+	require.Equal(t, dtos, got, string(content))
+
+	again, err := dtosToICS(got, now)
+	require.NoError(t, err)
+	require.Equal(t, string(content), string(again))
+}
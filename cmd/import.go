@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/jlz22/listly/core"
 	"github.com/spf13/cobra"
@@ -13,52 +15,98 @@ import (
 )
 
 type taskDTO struct {
-	Description string `json:"description" yaml:"description"`
-	Done        bool   `json:"done" yaml:"done"`
+	Description string    `json:"description" yaml:"description"`
+	Done        bool      `json:"done" yaml:"done"`
+	Priority    string    `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Tags        []string  `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Due         string    `json:"due,omitempty" yaml:"due,omitempty"`
+	Scheduled   string    `json:"scheduled,omitempty" yaml:"scheduled,omitempty"`
+	Recur       string    `json:"recur,omitempty" yaml:"recur,omitempty"` // e.g. "weekly on mon", see core.ParseRecurrence
+	Notes       string    `json:"notes,omitempty" yaml:"notes,omitempty"`
+	CreatedAt   string    `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	UpdatedAt   string    `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+	CompletedAt string    `json:"completed_at,omitempty" yaml:"completed_at,omitempty"`
+	UUID        string    `json:"uuid,omitempty" yaml:"uuid,omitempty"`       // see core.Task.UUID
+	Id          int       `json:"id,omitempty" yaml:"id,omitempty"`           // only exported with --ids
+	NextId      int       `json:"next_id,omitempty" yaml:"next_id,omitempty"` // see core.Task.NextId, only exported with --ids
+	Tasks       []taskDTO `json:"tasks,omitempty" yaml:"tasks,omitempty"`     // subtasks
 }
 
 type listDTO struct {
-	Title string    `json:"title" yaml:"title"`
-	Tasks []taskDTO `json:"tasks" yaml:"tasks"`
+	Title     string           `json:"title" yaml:"title"`
+	CreatedAt string           `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	UpdatedAt string           `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+	NextId    int              `json:"next_id,omitempty" yaml:"next_id,omitempty"`   // see core.ListInfo.NextId, only exported with --ids
+	Metadata  *listMetadataDTO `json:"metadata,omitempty" yaml:"metadata,omitempty"` // only exported with --metadata
+	Tasks     []taskDTO        `json:"tasks" yaml:"tasks"`
 }
 
+type listMetadataDTO struct {
+	Current bool `json:"current" yaml:"current"`
+	Tasks   int  `json:"tasks" yaml:"tasks"`
+	Done    int  `json:"done" yaml:"done"`
+	Pending int  `json:"pending" yaml:"pending"`
+}
+
+// the list for imported tasks whose file format does not name one
+const defaultListName = "inbox"
+
+var importColumns string
+var importOnConflict string
+var importMergeBy string
+var importFormat string
+
 var ImportCmd = &cobra.Command{
 	Use:   "import <file>",
-	Short: "Import tasks from a file. Supported formats: JSON, YAML",
+	Long:  "Import tasks from a file, or from stdin if the file is -. The format is picked from the file extension unless --format is given.",
+	Short: "Import tasks from a file. Supported formats: JSON, YAML, Markdown, todo.txt, CSV, TSV, iCalendar, Org",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		fileName := args[0]
-		content, err := os.ReadFile(fileName)
+		ext, err := formatExt(fileName, importFormat)
 		if err != nil {
 			return err
 		}
-		lists, err := fileToData(content, filepath.Ext(fileName))
+		var content []byte
+		if fileName == stdioFileName {
+			content, err = io.ReadAll(os.Stdin)
+		} else {
+			content, err = os.ReadFile(fileName)
+		}
 		if err != nil {
 			return err
 		}
-		err = core.WithDefaultDB(func(db *core.DB) error {
-			for _, list := range lists {
-				exists, err := db.ListExists(list.Info.Name)
-				if err != nil {
-					return err
-				}
-				if exists {
-					return fmt.Errorf("failed to import because list %q already exists", list.Info.Name)
-				}
+		lists, current, err := fileToData(content, ext)
+		if err != nil {
+			return err
+		}
+		opts := core.ImportOptions{Current: current}
+		opts.OnConflict, err = core.ParseConflictStrategy(importOnConflict)
+		if err != nil {
+			return err
+		}
+		switch importMergeBy {
+		case "description":
+		case "id":
+			opts.MergeById = true
+		default:
+			return fmt.Errorf("invalid --merge-by %q - expected description or id", importMergeBy)
+		}
 
-				err = db.SaveList(list)
-				if err != nil {
-					return err
-				}
-			}
-			return nil
+		var results []core.ImportResult
+		err = core.WithDefaultDB(func(db *core.DB) error {
+			results, err = db.ImportLists(lists, opts)
+			return err
 		})
 		if err != nil {
-			return err
+			if opts.OnConflict == core.ConflictFail {
+				return fmt.Errorf("failed to import: %v - use --on-conflict to skip, replace, merge or rename existing lists", err)
+			}
+			return fmt.Errorf("failed to import: %v", err)
 		}
 		fmt.Printf("Imported the following lists:\n")
-		for _, list := range lists {
-			fmt.Println("  - ", list.Info.Name)
+		for _, result := range results {
+			fmt.Println("  - ", result.Summary())
 		}
 		return nil
 	},
@@ -66,16 +114,27 @@ var ImportCmd = &cobra.Command{
 
 func setUpImport() {
 	RootCmd.AddCommand(ImportCmd)
+	ImportCmd.Flags().StringVar(&importFormat, "format", "", "The format of the file, overriding its extension: json, yaml, markdown, todotxt, csv, tsv, ical, org or taskwarrior. Required for stdin")
+	ImportCmd.Flags().StringVar(&importOnConflict, "on-conflict", "fail", "What to do with lists that already exist: fail, skip, replace, merge or rename")
+	ImportCmd.Flags().StringVar(&importMergeBy, "merge-by", "description", "How --on-conflict=merge finds tasks that are already in a list: description or id")
+	ImportCmd.Flags().StringVar(&importColumns, "columns", "", "Map CSV/TSV headers onto task fields, e.g. \"Task=description,Status=done,Project=list\"")
 }
 
-func fileToData(content []byte, ext string) ([]core.List, error) {
+// Convert the content of a file with the given extension into lists. Also
+// returns the list marked as current in the metadata of the file, if any.
+func fileToData(content []byte, ext string) ([]core.List, string, error) {
 	var dtos []listDTO
 	var lists []core.List
+	var current string
 	var err error
 
 	// unmarshal based on file extension
 	switch ext {
 	case ".json":
+		if isTaskwarriorJSON(content) {
+			dtos, err = taskwarriorToDTOs(content)
+			break
+		}
 		dec := json.NewDecoder(bytes.NewReader(content))
 		dec.DisallowUnknownFields() // ensure no unknown fields
 		err = dec.Decode(&dtos)
@@ -83,21 +142,270 @@ func fileToData(content []byte, ext string) ([]core.List, error) {
 		dec := yaml.NewDecoder(bytes.NewReader(content))
 		dec.KnownFields(true)
 		err = dec.Decode(&dtos)
+	case ".md":
+		dtos, err = markdownToDTOs(content)
+	case ".txt":
+		dtos, err = todoTxtToDTOs(content)
+	case ".csv", ".tsv":
+		dtos, err = csvToDTOs(content, csvDelimiter(ext), importColumns)
+	case ".ics":
+		dtos, err = icsToDTOs(content)
+	case ".org":
+		dtos, err = orgToDTOs(content)
 	default:
-		return lists, fmt.Errorf("unsupported file format: \"%s\". Supported formats are JSON and YAML", ext)
+		return lists, "", fmt.Errorf("unsupported file format: \"%s\". Supported formats are JSON, YAML, Markdown, todo.txt, CSV, TSV, iCalendar and Org", ext)
 	}
 	if err != nil {
-		return lists, err
+		return lists, "", err
 	}
 
 	// convert dtos into lists
 	lists = make([]core.List, len(dtos))
 	for i, dto := range dtos {
 		list := core.NewList(dto.Title)
-		for _, task := range dto.Tasks {
-			list.AddNewTask(task.Description, task.Done)
+		withIds, withoutIds := countDTOIds(dto.Tasks)
+		if withIds > 0 && withoutIds > 0 {
+			return nil, "", fmt.Errorf("list %q: %d tasks have an id and %d do not - either all tasks or none must have one", dto.Title, withIds, withoutIds)
+		}
+		err = addTaskDTOs(&list, dto.Tasks, 0)
+		if err != nil {
+			return nil, "", fmt.Errorf("list %q: %v", dto.Title, err)
+		}
+		// links to occurrences that were not exported are dropped
+		for _, task := range list.Tasks {
+			if _, ok := list.Tasks[task.NextId]; !ok {
+				task.NextId = 0
+			}
+		}
+		list.Info.NextId = max(list.Info.NextId, dto.NextId)
+		err = setDTOTimestamps(&list.Info.CreatedAt, &list.Info.UpdatedAt, dto.CreatedAt, dto.UpdatedAt)
+		if err != nil {
+			return nil, "", fmt.Errorf("list %q: %v", dto.Title, err)
 		}
 		lists[i] = list
+		if dto.Metadata != nil && dto.Metadata.Current {
+			current = dto.Title
+		}
+	}
+	return lists, current, nil
+}
+
+// add the tasks described by the dtos (and their subtasks) to the list under the given parent
+func addTaskDTOs(list *core.List, dtos []taskDTO, parentId int) error {
+	for _, dto := range dtos {
+		task, err := dtoToTask(list, dto)
+		if err != nil {
+			return fmt.Errorf("task %q: %v", dto.Description, err)
+		}
+		task.ParentId = parentId
+		err = list.AddTask(task)
+		if err != nil {
+			return err
+		}
+		err = addTaskDTOs(list, dto.Tasks, task.Id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// count the dtos (and their subtasks) with and without an id
+func countDTOIds(dtos []taskDTO) (int, int) {
+	withIds, withoutIds := 0, 0
+	for _, dto := range dtos {
+		if dto.Id != 0 {
+			withIds++
+		} else {
+			withoutIds++
+		}
+		subWith, subWithout := countDTOIds(dto.Tasks)
+		withIds += subWith
+		withoutIds += subWithout
+	}
+	return withIds, withoutIds
+}
+
+// Convert a dto into a new task of the list without adding it to the list. The
+// task keeps the id of the dto if it has one.
+func dtoToTask(list *core.List, dto taskDTO) (core.Task, error) {
+	var task core.Task
+	var err error
+	if dto.Id != 0 {
+		task, err = list.NewTaskWithId(dto.Id, dto.Description, dto.Done)
+		task.NextId = dto.NextId
+	} else {
+		task, err = list.NewTask(dto.Description, dto.Done)
+	}
+	if err != nil {
+		return task, err
+	}
+	task.Priority, err = core.ParsePriority(dto.Priority)
+	if err != nil {
+		return task, err
+	}
+	for _, tag := range dto.Tags {
+		task.Tags = append(task.Tags, core.NormalizeTag(tag))
+	}
+	task.Due, err = parseDTODate(dto.Due)
+	if err != nil {
+		return task, err
+	}
+	task.Scheduled, err = parseDTODate(dto.Scheduled)
+	if err != nil {
+		return task, err
+	}
+	task.Recurrence, err = core.ParseRecurrence(dto.Recur)
+	if err != nil {
+		return task, err
+	}
+	task.Notes = strings.TrimRight(dto.Notes, "\n")
+	if dto.UUID != "" {
+		task.UUID = dto.UUID
+	}
+	err = setDTOTimestamps(&task.CreatedAt, &task.UpdatedAt, dto.CreatedAt, dto.UpdatedAt)
+	if err != nil {
+		return task, err
+	}
+	if dto.CompletedAt != "" && task.Done {
+		task.CompletedAt, err = parseDTOTimestamp(dto.CompletedAt)
+		if err != nil {
+			return task, err
+		}
+	}
+	return task, nil
+}
+
+// Overwrite the creation and modification times with the ones from a dto. Missing
+// times keep their current value, and a missing update time defaults to the creation time.
+func setDTOTimestamps(createdAt, updatedAt *time.Time, created, updated string) error {
+	var err error
+	if created != "" {
+		*createdAt, err = parseDTOTimestamp(created)
+		if err != nil {
+			return err
+		}
+		*updatedAt = *createdAt
+	}
+	if updated != "" {
+		*updatedAt, err = parseDTOTimestamp(updated)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parse a creation, modification or completion time of a dto
+func parseDTOTimestamp(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return t, fmt.Errorf("invalid timestamp %q - expected RFC3339 (e.g. 2025-06-18T09:30:00Z)", s)
+	}
+	return t, nil
+}
+
+// format a creation, modification or completion time for a dto
+func formatDTOTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parse an optional date field of a taskDTO
+func parseDTODate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return core.ParseDate(s)
+}
+
+// format a priority for a taskDTO, leaving out tasks without a priority
+func formatDTOPriority(p core.Priority) string {
+	if p == core.PriorityNone {
+		return ""
+	}
+	return p.String()
+}
+
+// format an optional date for a taskDTO, keeping the time of day only if there is one
+func formatDTODate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if t.Equal(core.StartOfDay(t)) {
+		return core.FormatDate(t)
+	}
+	return t.Format(time.RFC3339)
+}
+
+// a task of a file format with one task per line or record, which names the
+// list it belongs to and refers to its parent by an id
+type flatTask struct {
+	dto       taskDTO
+	list      string
+	id        string
+	parent    string
+	subtasks  []*flatTask
+	emptyList bool // not a task, only keeps a list without tasks
+}
+
+func (t *flatTask) toDTO() taskDTO {
+	dto := t.dto
+	for _, subtask := range t.subtasks {
+		dto.Tasks = append(dto.Tasks, subtask.toDTO())
+	}
+	return dto
+}
+
+// group the tasks into lists in the order the lists first appear, with subtasks under their parents
+func flatTasksToDTOs(tasks []*flatTask) ([]listDTO, error) {
+	byId := map[string]*flatTask{}
+	for _, task := range tasks {
+		if task.id == "" {
+			continue
+		}
+		if _, ok := byId[task.id]; ok {
+			return nil, fmt.Errorf("id %q is used by more than one task", task.id)
+		}
+		byId[task.id] = task
+	}
+
+	titles := []string{}
+	tasksByTitle := map[string][]*flatTask{}
+	for _, task := range tasks {
+		if task.parent != "" {
+			parent, ok := byId[task.parent]
+			if !ok {
+				return nil, fmt.Errorf("task %q: there is no task with id %q", task.dto.Description, task.parent)
+			}
+			// following the parents for len(tasks) steps is enough to get around any cycle
+			ancestor := parent
+			for steps := 0; ancestor != nil && steps < len(tasks); steps++ {
+				if ancestor == task {
+					return nil, fmt.Errorf("task %q: parent %q would make the task its own subtask", task.dto.Description, task.parent)
+				}
+				ancestor = byId[ancestor.parent]
+			}
+			parent.subtasks = append(parent.subtasks, task)
+			continue
+		}
+		if _, ok := tasksByTitle[task.list]; !ok {
+			titles = append(titles, task.list)
+			tasksByTitle[task.list] = []*flatTask{}
+		}
+		if !task.emptyList {
+			tasksByTitle[task.list] = append(tasksByTitle[task.list], task)
+		}
+	}
+
+	dtos := make([]listDTO, len(titles))
+	for i, title := range titles {
+		dtos[i] = listDTO{Title: title, Tasks: []taskDTO{}}
+		for _, task := range tasksByTitle[title] {
+			dtos[i].Tasks = append(dtos[i].Tasks, task.toDTO())
+		}
 	}
-	return lists, nil
+	return dtos, nil
 }
@@ -5,12 +5,14 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jlz22/listly/core"
 	"github.com/spf13/cobra"
 )
 
 var useQuotes bool
+var listVerbose bool
 
 var ListCmd = &cobra.Command{
 	Use:   "list",
@@ -57,8 +59,14 @@ var ListCmd = &cobra.Command{
 			})
 
 			// display in a table format
-			printRow("List Name", "Pending", "Done", "Total", maxLen)
-			fmt.Println(strings.Repeat("=", maxLen+21)) // Print a separator line
+			separatorLen := maxLen + 21
+			if listVerbose {
+				printRow("List Name", "Pending", "Done", "Total", maxLen, "Created", "Updated")
+				separatorLen += 2 * (len(core.TimestampLayout) + 2)
+			} else {
+				printRow("List Name", "Pending", "Done", "Total", maxLen)
+			}
+			fmt.Println(strings.Repeat("=", separatorLen)) // Print a separator line
 			for _, info := range allInfoSlice {
 				var name string
 				if useQuotes {
@@ -66,6 +74,10 @@ var ListCmd = &cobra.Command{
 				} else {
 					name = info.Name
 				}
+				var extra []string
+				if listVerbose {
+					extra = []string{formatTimestamp(info.CreatedAt), formatTimestamp(info.UpdatedAt)}
+				}
 				if info.Name == currentListName {
 					printRow(
 						fmt.Sprintf("%s (current)", name),
@@ -73,6 +85,7 @@ var ListCmd = &cobra.Command{
 						strconv.Itoa(info.NumDone),
 						strconv.Itoa(info.NumTasks),
 						maxLen,
+						extra...,
 					)
 				} else {
 					printRow(
@@ -81,6 +94,7 @@ var ListCmd = &cobra.Command{
 						strconv.Itoa(info.NumDone),
 						strconv.Itoa(info.NumTasks),
 						maxLen,
+						extra...,
 					)
 				}
 			}
@@ -92,8 +106,26 @@ var ListCmd = &cobra.Command{
 func setUpList() {
 	RootCmd.AddCommand(ListCmd)
 	ListCmd.Flags().BoolVarP(&useQuotes, "quotes", "q", false, "Use quotes around list names")
+	ListCmd.Flags().BoolVarP(&listVerbose, "verbose", "v", false, "Also show when each list was created and last updated")
+}
+
+// print a row of the list table. Extra columns (e.g. timestamps) are printed after the total.
+func printRow(name, pending, done, total string, maxLen int, extra ...string) {
+	if len(extra) == 0 {
+		fmt.Printf("%-*s %-*s %-*s %-*s\n", maxLen, name, 8, pending, 5, done, 0, total)
+		return
+	}
+	row := fmt.Sprintf("%-*s %-*s %-*s %-*s", maxLen, name, 8, pending, 5, done, 6, total)
+	for _, col := range extra {
+		row += fmt.Sprintf(" %-*s", len(core.TimestampLayout)+1, col)
+	}
+	fmt.Println(strings.TrimRight(row, " "))
 }
 
-func printRow(name, pending, done, total string, maxLen int) {
-	fmt.Printf("%-*s %-*s %-*s %-*s\n", maxLen, name, 8, pending, 5, done, 0, total)
+// format a creation or modification time for display, or "-" if it is unknown
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(core.TimestampLayout)
 }
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jlz22/listly/core"
+	"github.com/spf13/cobra"
+)
+
+var logMaxCount int
+var logVerbose bool
+
+var LogCmd = &cobra.Command{
+	Use:   "log [list name]",
+	Short: "Show the history of changes to all lists or to the given list, newest first. Roll a list back with `listly restore <entry>`.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listName := ""
+		if len(args) == 1 {
+			listName = args[0]
+		}
+
+		return core.WithDefaultDB(func(db *core.DB) error {
+			entries, err := db.GetJournal(listName)
+			if err != nil {
+				return fmt.Errorf("could not retrieve history due to the following error\n\t %v", err)
+			}
+			if len(entries) == 0 {
+				if listName != "" {
+					return fmt.Errorf("no history found for list %q", listName)
+				}
+				return fmt.Errorf("no history found")
+			}
+			if logMaxCount > 0 && len(entries) > logMaxCount {
+				entries = entries[:logMaxCount]
+			}
+
+			idWidth := len(strconv.Itoa(entries[0].Id))
+			nameWidth := 0
+			for _, entry := range entries {
+				nameWidth = max(nameWidth, len(entry.List))
+			}
+			for _, entry := range entries {
+				fmt.Printf("%*d  %s  %-7s  %-*s  %s\n",
+					idWidth, entry.Id, formatTimestamp(entry.Time), entry.Operation,
+					nameWidth, entry.List, entry.Summary())
+				if logVerbose {
+					printDiff(entry.Diff(), idWidth+2)
+				}
+			}
+			return nil
+		})
+	},
+}
+
+func setUpLog() {
+	RootCmd.AddCommand(LogCmd)
+	LogCmd.Flags().IntVarP(&logMaxCount, "max-count", "n", 0, "Only show this many entries")
+	LogCmd.Flags().BoolVarP(&logVerbose, "verbose", "v", false, "Also show the tasks that were added (+), changed (~) and removed (-)")
+}
+
+// print the tasks of a diff one per line, indented by the given number of spaces
+func printDiff(diff core.ListDiff, indent int) {
+	groups := []struct {
+		marker string
+		tasks  []*core.Task
+	}{
+		{"+", diff.Added},
+		{"~", diff.Changed},
+		{"-", diff.Removed},
+	}
+	for _, group := range groups {
+		for _, task := range group.tasks {
+			box := "[ ]"
+			if task.Done {
+				box = "[x]"
+			}
+			fmt.Printf("%*s%s %s %s\n", indent, "", group.marker, box, task.Description)
+		}
+	}
+}
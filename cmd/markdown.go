@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GitHub-style markdown checklists. Every `#` or `##` heading starts a list and
+// every `- [ ]` or `- [x]` item below it is a task. Items indented under another
+// item are its subtasks, and other lines indented under an item are its notes.
+// Everything else, including fenced code blocks, is ignored.
+
+var mdHeading = regexp.MustCompile(`^(#{1,2})\s+(.*?)(?:\s+#+)?\s*$`)
+var mdItem = regexp.MustCompile(`^[-*+]\s+\[([ xX])\]\s*(.*)$`)
+
+// a task read from markdown before it is turned into a taskDTO
+type mdTask struct {
+	dto      taskDTO
+	indent   int
+	notes    []string
+	subtasks []*mdTask
+}
+
+func (t *mdTask) toDTO() taskDTO {
+	dto := t.dto
+	dto.Notes = strings.TrimRight(strings.Join(t.notes, "\n"), "\n")
+	for _, subtask := range t.subtasks {
+		dto.Tasks = append(dto.Tasks, subtask.toDTO())
+	}
+	return dto
+}
+
+func markdownToDTOs(content []byte) ([]listDTO, error) {
+	titles := []string{}
+	tasksByTitle := map[string][]*mdTask{}
+	var title string
+	var stack []*mdTask // the last task at each level of nesting
+	inCode := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.ReplaceAll(scanner.Text(), "\t", "    ")
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		if parts := mdHeading.FindStringSubmatch(line); parts != nil {
+			title = parts[2]
+			if title == "" {
+				return nil, fmt.Errorf("line %d: headings must contain a list title", lineNum)
+			}
+			if _, ok := tasksByTitle[title]; !ok {
+				titles = append(titles, title)
+				tasksByTitle[title] = []*mdTask{}
+			}
+			stack = nil
+			continue
+		}
+
+		if parts := mdItem.FindStringSubmatch(trimmed); parts != nil {
+			description := strings.TrimSpace(parts[2])
+			if description == "" {
+				continue // empty template item
+			}
+			if title == "" {
+				return nil, fmt.Errorf("line %d: checklist item %q is not under a heading - add a # heading with the list title above it", lineNum, description)
+			}
+			task := &mdTask{dto: taskDTO{Description: description, Done: parts[1] != " "}, indent: indent}
+			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				tasksByTitle[title] = append(tasksByTitle[title], task)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.subtasks = append(parent.subtasks, task)
+			}
+			stack = append(stack, task)
+			continue
+		}
+
+		// notes of the last item if indented under it
+		if len(stack) > 0 {
+			last := stack[len(stack)-1]
+			if trimmed == "" {
+				if len(last.notes) > 0 {
+					last.notes = append(last.notes, "")
+				}
+				continue
+			}
+			if indent > last.indent && len(last.subtasks) == 0 {
+				last.notes = append(last.notes, trimmed)
+				continue
+			}
+			stack = nil // anything else ends the checklist
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	dtos := make([]listDTO, len(titles))
+	for i, title := range titles {
+		dtos[i] = listDTO{Title: title, Tasks: []taskDTO{}}
+		for _, task := range tasksByTitle[title] {
+			dtos[i].Tasks = append(dtos[i].Tasks, task.toDTO())
+		}
+	}
+	return dtos, nil
+}
+
+func dtosToMarkdown(dtos []listDTO) []byte {
+	var b strings.Builder
+	for i, dto := range dtos {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "# %s\n\n", dto.Title)
+		writeMarkdownTasks(&b, dto.Tasks, "")
+	}
+	return []byte(b.String())
+}
+
+func writeMarkdownTasks(b *strings.Builder, dtos []taskDTO, indent string) {
+	for _, dto := range dtos {
+		box := "[ ]"
+		if dto.Done {
+			box = "[x]"
+		}
+		fmt.Fprintf(b, "%s- %s %s\n", indent, box, dto.Description)
+		if dto.Notes != "" {
+			for _, line := range strings.Split(dto.Notes, "\n") {
+				if line == "" {
+					b.WriteString("\n")
+				} else {
+					fmt.Fprintf(b, "%s  %s\n", indent, line)
+				}
+			}
+		}
+		writeMarkdownTasks(b, dto.Tasks, indent+"  ")
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownToDTOs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []listDTO
+	}{
+		{
+			name:    "headings and items",
+			content: "# work\n\n- [ ] review\n* [x] deploy\n## home\n+ [X] dishes\n",
+			want: []listDTO{
+				{Title: "work", Tasks: []taskDTO{{Description: "review"}, {Description: "deploy", Done: true}}},
+				{Title: "home", Tasks: []taskDTO{{Description: "dishes", Done: true}}},
+			},
+		},
+		{
+			name:    "nested levels",
+			content: "# work\n- [ ] a\n  - [ ] b\n    - [x] c\n  - [ ] d\n\t- [ ] e\n- [ ] f\n",
+			want: []listDTO{{Title: "work", Tasks: []taskDTO{
+				{Description: "a", Tasks: []taskDTO{
+					{Description: "b", Tasks: []taskDTO{{Description: "c", Done: true}}},
+					{Description: "d", Tasks: []taskDTO{{Description: "e"}}}, // a tab counts as 4 spaces
+				}},
+				{Description: "f"},
+			}}},
+		},
+		{
+			name:    "notes",
+			content: "# work\n- [ ] a\n  first line\n\n  second line\n- [ ] b\nnot a note\n",
+			want: []listDTO{{Title: "work", Tasks: []taskDTO{
+				{Description: "a", Notes: "first line\n\nsecond line"},
+				{Description: "b"},
+			}}},
+		},
+		{
+			name:    "code blocks, empty items and repeated headings",
+			content: "Intro text\n# work\n```\n- [ ] not a task\n```\n- [ ] \n- [ ] a\n# home\n# work ##\n- [ ] b\n",
+			want: []listDTO{
+				{Title: "work", Tasks: []taskDTO{{Description: "a"}, {Description: "b"}}},
+				{Title: "home", Tasks: []taskDTO{}},
+			},
+		},
+		{
+			name:    "closing hashes",
+			content: "# C#\n- [ ] a\n## F# ##  \n- [ ] b\n# issue #12 #\n",
+			want: []listDTO{
+				{Title: "C#", Tasks: []taskDTO{{Description: "a"}}},
+				{Title: "F#", Tasks: []taskDTO{{Description: "b"}}},
+				{Title: "issue #12", Tasks: []taskDTO{}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := markdownToDTOs([]byte(tt.content))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMarkdownToDTOs_Errors(t *testing.T) {
+	for _, content := range []string{
+		"- [ ] no heading\n",
+		"#   \n- [ ] a\n",
+	} {
+		_, err := markdownToDTOs([]byte(content))
+		require.Error(t, err, content)
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	dtos := []listDTO{
+		{Title: "work", Tasks: []taskDTO{
+			{Description: "a", Notes: "line one\n\nline two", Tasks: []taskDTO{
+				{Description: "b", Done: true, Tasks: []taskDTO{{Description: "c"}}},
+			}},
+			{Description: "d #tag"},
+		}},
+		{Title: "C#", Tasks: []taskDTO{{Description: "e"}}},
+		{Title: "empty", Tasks: []taskDTO{}},
+	}
+	got, err := markdownToDTOs(dtosToMarkdown(dtos))
+	require.NoError(t, err)
+	require.Equal(t, dtos, got)
+}
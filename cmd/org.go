@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jlz22/listly/core"
+)
+
+// Org-mode files. Every top-level headline is a list and every TODO or DONE
+// headline below it is a task, with deeper TODO and DONE headlines as its
+// subtasks. The priority cookie, tags, SCHEDULED, DEADLINE and CLOSED lines
+// and repeaters are mapped onto tasks, and the text below a task is its notes.
+// The CREATED property holds the creation time and the RECUR property holds
+// recurrences that cannot be written as a repeater. Other headlines are ignored.
+
+var orgHeadline = regexp.MustCompile(`^(\*+)\s+(.*?)\s*$`)
+var orgTags = regexp.MustCompile(`\s+:([\w@#%\-./:]+):$`)
+var orgPriority = regexp.MustCompile(`^\[#([A-Z])\]\s*`)
+var orgPlanning = regexp.MustCompile(`(SCHEDULED|DEADLINE|CLOSED):\s*([<\[][^>\]]*[>\]])`)
+var orgTimestamp = regexp.MustCompile(`^[<\[](\d{4}-\d{2}-\d{2})(?:\s+[^\s\d>\]]+)?(?:\s+(\d{1,2}:\d{2}))?(?:\s+(\S+))?[^>\]]*[>\]]$`)
+var orgDrawer = regexp.MustCompile(`^:([\w-]+):$`)
+var orgProperty = regexp.MustCompile(`^:([\w-]+):\s*(.*)$`)
+
+// a task read from an org file before it is turned into a taskDTO
+type orgTask struct {
+	dto      taskDTO
+	level    int
+	notes    []string
+	subtasks []*orgTask
+}
+
+func (t *orgTask) toDTO() taskDTO {
+	dto := t.dto
+	dto.Notes = strings.Trim(strings.Join(trimCommonIndent(t.notes), "\n"), "\n")
+	for _, subtask := range t.subtasks {
+		dto.Tasks = append(dto.Tasks, subtask.toDTO())
+	}
+	return dto
+}
+
+func orgToDTOs(content []byte) ([]listDTO, error) {
+	titles := []string{}
+	tasksByTitle := map[string][]*orgTask{}
+	var title string
+	var stack []*orgTask // the task headlines the current line is nested in
+	var task *orgTask    // the task whose body the current line is in, if any
+	drawer := ""         // the drawer the current line is in, if any
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if parts := orgHeadline.FindStringSubmatch(line); parts != nil {
+			level := len(parts[1])
+			text := orgTags.ReplaceAllString(parts[2], "")
+			task = nil
+			drawer = ""
+			for len(stack) > 0 && stack[len(stack)-1].level >= level {
+				stack = stack[:len(stack)-1]
+			}
+
+			if level == 1 {
+				title = text
+				if _, ok := tasksByTitle[title]; !ok {
+					titles = append(titles, title)
+					tasksByTitle[title] = []*orgTask{}
+				}
+				continue
+			}
+			keyword, description, _ := strings.Cut(text, " ")
+			if (keyword != "TODO" && keyword != "DONE") || title == "" {
+				continue
+			}
+
+			task = &orgTask{level: level, dto: taskDTO{Done: keyword == "DONE"}}
+			if priority := orgPriority.FindStringSubmatch(description); priority != nil {
+				task.dto.Priority = parseLetterPriority(priority[1])
+				description = description[len(priority[0]):]
+			}
+			task.dto.Description = strings.TrimSpace(description)
+			if task.dto.Description == "" {
+				return nil, fmt.Errorf("line %d: task has no description", lineNum)
+			}
+			if tags := orgTags.FindStringSubmatch(parts[2]); tags != nil {
+				for _, tag := range strings.Split(tags[1], ":") {
+					if tag != "" {
+						task.dto.Tags = append(task.dto.Tags, tag)
+					}
+				}
+			}
+
+			if len(stack) == 0 {
+				tasksByTitle[title] = append(tasksByTitle[title], task)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.subtasks = append(parent.subtasks, task)
+			}
+			stack = append(stack, task)
+			continue
+		}
+		if task == nil || strings.HasPrefix(trimmed, "#+") {
+			continue
+		}
+
+		// drawers and planning lines come before the notes of a task
+		if drawer != "" {
+			if strings.EqualFold(trimmed, ":END:") {
+				drawer = ""
+			} else if parts := orgProperty.FindStringSubmatch(trimmed); parts != nil && drawer == "PROPERTIES" {
+				err := setOrgProperty(task, strings.ToUpper(parts[1]), strings.TrimSpace(parts[2]))
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %v", lineNum, err)
+				}
+			}
+			continue
+		}
+		if len(task.notes) == 0 {
+			if parts := orgDrawer.FindStringSubmatch(trimmed); parts != nil {
+				drawer = strings.ToUpper(parts[1])
+				continue
+			}
+			if planning := orgPlanning.FindAllStringSubmatch(trimmed, -1); planning != nil && strings.HasPrefix(trimmed, planning[0][0]) {
+				for _, parts := range planning {
+					err := setOrgPlanning(task, parts[1], parts[2])
+					if err != nil {
+						return nil, fmt.Errorf("line %d: %v", lineNum, err)
+					}
+				}
+				continue
+			}
+		}
+		if trimmed != "" || len(task.notes) > 0 {
+			task.notes = append(task.notes, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	dtos := make([]listDTO, len(titles))
+	for i, title := range titles {
+		dtos[i] = listDTO{Title: title, Tasks: []taskDTO{}}
+		for _, task := range tasksByTitle[title] {
+			dtos[i].Tasks = append(dtos[i].Tasks, task.toDTO())
+		}
+	}
+	return dtos, nil
+}
+
+func setOrgPlanning(task *orgTask, keyword string, timestamp string) error {
+	value, repeater, err := parseOrgTimestamp(timestamp)
+	if err != nil {
+		return fmt.Errorf("%s: %v", keyword, err)
+	}
+	switch keyword {
+	case "SCHEDULED":
+		task.dto.Scheduled = value
+	case "DEADLINE":
+		task.dto.Due = value
+	case "CLOSED":
+		t, err := core.ParseDate(value)
+		if err != nil {
+			return err
+		}
+		task.dto.CompletedAt = t.Format(time.RFC3339)
+	}
+	if recur, ok := parseRepeater(repeater); ok && task.dto.Recur == "" {
+		task.dto.Recur = recur
+	}
+	return nil
+}
+
+func setOrgProperty(task *orgTask, key string, value string) error {
+	switch key {
+	case "CREATED":
+		created, _, err := parseOrgTimestamp(value)
+		if err != nil {
+			return fmt.Errorf("CREATED: %v", err)
+		}
+		t, err := core.ParseDate(created)
+		if err != nil {
+			return err
+		}
+		task.dto.CreatedAt = t.Format(time.RFC3339)
+	case "RECUR":
+		task.dto.Recur = value
+	}
+	return nil
+}
+
+// Parse a timestamp such as <2025-06-20 Fri 10:00 +1w> into a dto date, which
+// only has a time of day if the timestamp has one, and its repeater, if any.
+func parseOrgTimestamp(timestamp string) (string, string, error) {
+	parts := orgTimestamp.FindStringSubmatch(timestamp)
+	if parts == nil {
+		return "", "", fmt.Errorf("invalid timestamp %q - expected e.g. <2025-06-20 Fri> or <2025-06-20 Fri 10:00>", timestamp)
+	}
+	if parts[2] == "" {
+		return parts[1], parts[3], nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", parts[1]+" "+parts[2], time.Local)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	return t.Format(time.RFC3339), parts[3], nil
+}
+
+// format a dto date as an org timestamp with the given brackets, keeping the time of day only if there is one
+func formatOrgTimestamp(value string, open string, close string, repeater string) (string, error) {
+	t, err := core.ParseDate(value)
+	if err != nil {
+		return "", err
+	}
+	t = t.Local()
+	layout := "2006-01-02 Mon"
+	if len(value) != len(core.DateLayout) {
+		layout += " 15:04"
+	}
+	if repeater != "" {
+		repeater = " +" + repeater
+	}
+	return open + t.Format(layout) + repeater + close, nil
+}
+
+// remove the indentation shared by all non-blank lines
+func trimCommonIndent(lines []string) []string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	trimmed := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		trimmed[i] = strings.TrimRight(line, " \t")
+	}
+	return trimmed
+}
+
+func dtosToOrg(dtos []listDTO) ([]byte, error) {
+	var b strings.Builder
+	for _, dto := range dtos {
+		fmt.Fprintf(&b, "* %s\n", dto.Title)
+		err := writeOrgTasks(&b, dto.Tasks, 2)
+		if err != nil {
+			return nil, fmt.Errorf("list %q: %v", dto.Title, err)
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+func writeOrgTasks(b *strings.Builder, dtos []taskDTO, level int) error {
+	indent := strings.Repeat(" ", level+1)
+	for _, dto := range dtos {
+		headline := strings.Repeat("*", level) + " TODO "
+		if dto.Done {
+			headline = strings.Repeat("*", level) + " DONE "
+		}
+		if priority := formatLetterPriority(dto.Priority); priority != "" {
+			headline += "[#" + priority + "] "
+		}
+		headline += dto.Description
+		if len(dto.Tags) > 0 {
+			tags := make([]string, len(dto.Tags))
+			for i, tag := range dto.Tags {
+				tags[i] = strings.TrimPrefix(core.NormalizeTag(tag), "#")
+			}
+			headline += " :" + strings.Join(tags, ":") + ":"
+		}
+		b.WriteString(headline + "\n")
+
+		// recurrences on given weekdays have no repeater and go into the RECUR property
+		repeater := formatRepeater(dto.Recur)
+		recurrence, _ := core.ParseRecurrence(dto.Recur)
+		if len(recurrence.Weekdays) > 0 || (dto.Due == "" && dto.Scheduled == "") {
+			repeater = ""
+		}
+		hasRepeater := repeater != ""
+
+		var planning []string
+		if dto.CompletedAt != "" {
+			closed, err := formatOrgTimestamp(dto.CompletedAt, "[", "]", "")
+			if err != nil {
+				return fmt.Errorf("task %q: %v", dto.Description, err)
+			}
+			planning = append(planning, "CLOSED: "+closed)
+		}
+		if dto.Due != "" {
+			deadline, err := formatOrgTimestamp(dto.Due, "<", ">", repeater)
+			if err != nil {
+				return fmt.Errorf("task %q: %v", dto.Description, err)
+			}
+			planning = append(planning, "DEADLINE: "+deadline)
+			repeater = ""
+		}
+		if dto.Scheduled != "" {
+			scheduled, err := formatOrgTimestamp(dto.Scheduled, "<", ">", repeater)
+			if err != nil {
+				return fmt.Errorf("task %q: %v", dto.Description, err)
+			}
+			planning = append(planning, "SCHEDULED: "+scheduled)
+		}
+		if len(planning) > 0 {
+			b.WriteString(indent + strings.Join(planning, " ") + "\n")
+		}
+
+		var properties []string
+		if dto.CreatedAt != "" {
+			created, err := formatOrgTimestamp(dto.CreatedAt, "[", "]", "")
+			if err != nil {
+				return fmt.Errorf("task %q: %v", dto.Description, err)
+			}
+			properties = append(properties, ":CREATED: "+created)
+		}
+		if dto.Recur != "" && !hasRepeater {
+			properties = append(properties, ":RECUR: "+dto.Recur)
+		}
+		if len(properties) > 0 {
+			b.WriteString(indent + ":PROPERTIES:\n")
+			for _, property := range properties {
+				b.WriteString(indent + property + "\n")
+			}
+			b.WriteString(indent + ":END:\n")
+		}
+
+		if dto.Notes != "" {
+			for _, line := range strings.Split(dto.Notes, "\n") {
+				if line == "" {
+					b.WriteString("\n")
+				} else {
+					b.WriteString(indent + line + "\n")
+				}
+			}
+		}
+		err := writeOrgTasks(b, dto.Tasks, level+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// a dto timestamp in local time, which is how org timestamps are read
+func orgTime(day, hour, minute int) string {
+	return time.Date(2025, 6, day, hour, minute, 0, 0, time.Local).Format(time.RFC3339)
+}
+
+func TestOrgToDTOs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []listDTO
+	}{
+		{
+			name:    "headlines, priorities and tags",
+			content: "#+TITLE: tasks\n* work\n** TODO [#A] review :backend:@alice:front-end:v1.2/beta:\n** DONE deploy\n** Meeting notes\n* home\n** TODO [#C] dishes\n",
+			want: []listDTO{
+				{Title: "work", Tasks: []taskDTO{
+					{Description: "review", Priority: "urgent", Tags: []string{"backend", "@alice", "front-end", "v1.2/beta"}},
+					{Description: "deploy", Done: true},
+				}},
+				{Title: "home", Tasks: []taskDTO{{Description: "dishes", Priority: "medium"}}},
+			},
+		},
+		{
+			name:    "nested levels",
+			content: "* work\n** TODO a\n*** TODO b\n**** DONE c\n*** TODO d\n***** TODO e\n**** TODO f\n** TODO g\n",
+			want: []listDTO{{Title: "work", Tasks: []taskDTO{
+				{Description: "a", Tasks: []taskDTO{
+					{Description: "b", Tasks: []taskDTO{{Description: "c", Done: true}}},
+					{Description: "d", Tasks: []taskDTO{{Description: "e"}, {Description: "f"}}},
+				}},
+				{Description: "g"},
+			}}},
+		},
+		{
+			name: "planning lines, drawers and notes",
+			content: "* work\n** DONE report\n   CLOSED: [2025-06-03 Tue 10:00] DEADLINE: <2025-06-20 Fri +1w> SCHEDULED: <2025-06-18 Wed 09:30>\n" +
+				"   :PROPERTIES:\n   :CREATED: [2025-06-01 Sun 09:30]\n   :EFFORT: 1:00\n   :END:\n   :LOGBOOK:\n   - note\n   :END:\n" +
+				"\n   first line\n     indented\n\n   DEADLINE: <2025-06-21 Sat>\n** TODO gym\n   :PROPERTIES:\n   :RECUR: weekly on mon,thu\n   :END:\n",
+			want: []listDTO{{Title: "work", Tasks: []taskDTO{
+				{
+					Description: "report",
+					Done:        true,
+					Due:         "2025-06-20",
+					Scheduled:   orgTime(18, 9, 30),
+					Recur:       "every 1 weeks",
+					Notes:       "first line\n  indented\n\nDEADLINE: <2025-06-21 Sat>",
+					CreatedAt:   orgTime(1, 9, 30),
+					CompletedAt: orgTime(3, 10, 0),
+				},
+				{Description: "gym", Recur: "weekly on mon,thu"},
+			}}},
+		},
+		{
+			name:    "empty and repeated lists",
+			content: "Text before any headline\n** TODO ignored\n* empty\n* work\n** TODO a\n* work\n** TODO b\n",
+			want: []listDTO{
+				{Title: "empty", Tasks: []taskDTO{}},
+				{Title: "work", Tasks: []taskDTO{{Description: "a"}, {Description: "b"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := orgToDTOs([]byte(tt.content))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOrgToDTOs_Errors(t *testing.T) {
+	for _, content := range []string{
+		"* work\n** TODO [#A]\n",
+		"* work\n** TODO a\n   DEADLINE: <June 20th>\n",
+		"* work\n** TODO a\n   :PROPERTIES:\n   :CREATED: yesterday\n   :END:\n",
+	} {
+		_, err := orgToDTOs([]byte(content))
+		require.Error(t, err, content)
+	}
+}
+
+func TestOrgRoundTrip(t *testing.T) {
+	dtos := []listDTO{
+		{Title: "work", Tasks: []taskDTO{
+			{
+				Description: "review",
+				Priority:    "high",
+				Tags:        []string{"backend", "@alice", "front-end"},
+				Due:         "2025-06-20",
+				Scheduled:   orgTime(18, 9, 30),
+				Recur:       "every 2 weeks",
+				Notes:       "first\n\n  second",
+				CreatedAt:   orgTime(1, 9, 30),
+				Tasks: []taskDTO{
+					{Description: "sub", Done: true, CompletedAt: orgTime(3, 10, 0), Tasks: []taskDTO{
+						{Description: "subsub", Recur: "weekly on mon,thu", Scheduled: "2025-06-19"},
+					}},
+				},
+			},
+			{Description: "daily without a date", Recur: "daily"},
+		}},
+		{Title: "empty", Tasks: []taskDTO{}},
+	}
+	content, err := dtosToOrg(dtos)
+	require.NoError(t, err)
+	got, err := orgToDTOs(content)
+	require.NoError(t, err)
+	require.Equal(t, dtos, got, string(content))
+}
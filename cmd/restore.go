@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jlz22/listly/core"
+	"github.com/spf13/cobra"
+)
+
+var RestoreCmd = &cobra.Command{
+	Use:   "restore <entry>",
+	Short: "Roll a list back to the state it had before the given `listly log` entry. Deleted lists are recreated.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid entry %q - expected a number from `listly log`", args[0])
+		}
+
+		return core.WithDefaultDB(func(db *core.DB) error {
+			list, err := db.RestoreJournalEntry(id)
+			if err != nil {
+				return fmt.Errorf("could not restore entry %d due to the following error\n\t %v", id, err)
+			}
+			core.Success(fmt.Sprintf("Restored '%s' to its state before entry %d", list.Info.Name, id))
+			return nil
+		})
+	},
+}
+
+func setUpRestore() {
+	RootCmd.AddCommand(RestoreCmd)
+}
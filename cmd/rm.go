@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jlz22/listly/core"
+	"github.com/spf13/cobra"
+)
+
+var rmListName string
+var rmById bool
+
+var RmCmd = &cobra.Command{
+	Use:   "rm <task> [other tasks...]",
+	Short: "Remove tasks from a list. Subtasks of a removed task are moved up to its parent. Tasks are given by their position in `listly show -n` or by id with --id.",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return core.WithDefaultDB(func(db *core.DB) error {
+			list, err := getListOrCurrent(db, rmListName)
+			if err != nil {
+				return err
+			}
+			ids, err := resolveTaskIds(list, args, rmById)
+			if err != nil {
+				return err
+			}
+
+			removed := make([]string, len(ids))
+			for i, id := range ids {
+				removed[i] = list.Tasks[id].Description
+				if err := list.RemoveTask(id); err != nil {
+					return err
+				}
+			}
+			if err := db.SaveList(list); err != nil {
+				return err
+			}
+
+			fmt.Printf("Removed the following tasks from %q:\n%s\n", list.Info.Name, core.ListLists(removed, "  "))
+			return nil
+		})
+	},
+}
+
+func setUpRm() {
+	RootCmd.AddCommand(RmCmd)
+	RmCmd.Flags().StringVarP(&rmListName, "list", "l", "", "Use this list instead of the current one")
+	RmCmd.Flags().BoolVarP(&rmById, "id", "i", false, "Treat the arguments as task ids instead of positions")
+}
@@ -28,4 +28,14 @@ func SetUp() {
 	setUpAuth()
 	setUpGenerate()
 	setUpKmap()
+	setUpTags()
+	setUpAdd()
+	setUpDone()
+	setUpRm()
+	setUpEdit()
+	setUpLog()
+	setUpRestore()
+	setUpTrash()
+	setUpArchive()
+	setUpSearch()
 }
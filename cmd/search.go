@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jlz22/listly/core"
+	"github.com/spf13/cobra"
+)
+
+var searchOpts core.SearchOptions
+var searchJSON bool
+
+type searchMatchDTO struct {
+	Position int `json:"position"` // see `listly show -n`
+	Id       int `json:"id"`
+	taskDTO
+}
+
+type searchResultDTO struct {
+	List  string           `json:"list"`
+	Tasks []searchMatchDTO `json:"tasks"`
+}
+
+var SearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find tasks in all lists whose description, notes or tags contain the query. Matches are grouped by list and ignore case by default.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if searchOpts.OnlyDone && searchOpts.OnlyPending {
+			return fmt.Errorf("--done and --pending cannot be used together")
+		}
+
+		return core.WithDefaultDB(func(db *core.DB) error {
+			results, err := db.Search(args[0], searchOpts)
+			if err != nil {
+				return fmt.Errorf("could not search due to the following error\n\t %v", err)
+			}
+
+			if searchJSON {
+				dtos := make([]searchResultDTO, len(results))
+				for i, result := range results {
+					dtos[i] = searchResultDTO{List: result.List, Tasks: make([]searchMatchDTO, len(result.Matches))}
+					for j, match := range result.Matches {
+						dtos[i].Tasks[j] = searchMatchDTO{Position: match.Position, Id: match.Task.Id, taskDTO: taskToDTO(match.Task)}
+					}
+				}
+				content, err := json.MarshalIndent(dtos, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(content))
+				return nil
+			}
+
+			if len(results) == 0 {
+				fmt.Printf("No tasks match %q.\n", args[0])
+				return nil
+			}
+			numMatches := 0
+			for _, result := range results {
+				fmt.Println(result.List)
+				for _, match := range result.Matches {
+					box := "[ ]"
+					if match.Task.Done {
+						box = "[x]"
+					}
+					fmt.Printf("%4d %s %s\n", match.Position, box, match.Task.Description)
+				}
+				numMatches += len(result.Matches)
+			}
+			fmt.Printf("\n%d matching tasks in %d lists\n", numMatches, len(results))
+			return nil
+		})
+	},
+}
+
+func setUpSearch() {
+	RootCmd.AddCommand(SearchCmd)
+	SearchCmd.Flags().BoolVarP(&searchOpts.Regex, "regex", "r", false, "Treat the query as a regular expression")
+	SearchCmd.Flags().BoolVarP(&searchOpts.CaseSensitive, "case-sensitive", "c", false, "Match upper and lower case exactly")
+	SearchCmd.Flags().BoolVar(&searchOpts.OnlyDone, "done", false, "Only match completed tasks")
+	SearchCmd.Flags().BoolVar(&searchOpts.OnlyPending, "pending", false, "Only match pending tasks")
+	SearchCmd.Flags().BoolVar(&searchJSON, "json", false, "Print the matches as JSON")
+}
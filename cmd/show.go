@@ -2,16 +2,33 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/jlz22/listly/core"
 	"github.com/spf13/cobra"
 )
 
+var showByPriority bool
+var showTag string
+var showVerbose bool
+var showNumbered bool
+var showIds bool
+var showFilter string
+
 var ShowCmd = &cobra.Command{
 	Use:   "show [list name]",
 	Short: "Print all tasks in the current or specified list.",
 	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		var filter *core.Filter
+		if showFilter != "" {
+			var err error
+			filter, err = core.ParseFilter(showFilter, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
 		return core.WithDefaultDB(func(db *core.DB) error {
 			// Get the list name (current or specified)
 			var listName string
@@ -34,7 +51,14 @@ var ShowCmd = &cobra.Command{
 				return fmt.Errorf("could not retrieve list %s due to the following error\n\t %v", listName, err)
 			}
 
-			fmt.Printf("%v", &list)
+			fmt.Print(list.Render(core.RenderOptions{
+				ByPriority: showByPriority,
+				Tag:        showTag,
+				Verbose:    showVerbose,
+				Numbered:   showNumbered,
+				Ids:        showIds,
+				Filter:     filter,
+			}))
 			return nil
 		})
 	},
@@ -42,4 +66,10 @@ var ShowCmd = &cobra.Command{
 
 func setUpShow() {
 	RootCmd.AddCommand(ShowCmd)
+	ShowCmd.Flags().BoolVarP(&showByPriority, "by-priority", "p", false, "Sort pending tasks by priority")
+	ShowCmd.Flags().StringVarP(&showTag, "tag", "t", "", "Only show tasks carrying the given tag")
+	ShowCmd.Flags().BoolVarP(&showVerbose, "verbose", "v", false, "Print the notes of each task")
+	ShowCmd.Flags().BoolVarP(&showNumbered, "numbers", "n", false, "Print the position of each task for use with done, undone, rm and edit")
+	ShowCmd.Flags().StringVarP(&showFilter, "filter", "f", "", "Only show tasks matching a filter, e.g. 'pending and tag:backend and due<7d' (see the README)")
+	ShowCmd.Flags().BoolVar(&showIds, "ids", false, "Print the id of each task for use with --id in done, undone, rm and edit")
 }
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jlz22/listly/core"
+	"github.com/spf13/cobra"
+)
+
+var TagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Display every tag used across all lists along with the number of tasks carrying it.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return core.WithDefaultDB(func(db *core.DB) error {
+			counts, err := db.GetTagCounts()
+			if err != nil {
+				return fmt.Errorf("failed to retrieve tags: %v", err)
+			}
+
+			// end early if no tags found
+			if len(counts) == 0 {
+				return fmt.Errorf("no tags found - add #tags or @mentions to task descriptions to tag them")
+			}
+
+			// sort by count, then by name
+			tags := make([]string, 0, len(counts))
+			maxLen := len("Tag")
+			for tag := range counts {
+				tags = append(tags, tag)
+				maxLen = max(maxLen, len(tag))
+			}
+			sort.Slice(tags, func(i, j int) bool {
+				if counts[tags[i]] != counts[tags[j]] {
+					return counts[tags[i]] > counts[tags[j]]
+				}
+				return tags[i] < tags[j]
+			})
+
+			// display in a table format
+			fmt.Printf("%-*s %s\n", maxLen, "Tag", "Tasks")
+			fmt.Println(strings.Repeat("=", maxLen+6))
+			for _, tag := range tags {
+				fmt.Printf("%-*s %d\n", maxLen, tag, counts[tag])
+			}
+			return nil
+		})
+	},
+}
+
+func setUpTags() {
+	RootCmd.AddCommand(TagsCmd)
+}
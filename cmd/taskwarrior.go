@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jlz22/listly/core"
+)
+
+// Taskwarrior's `task export` JSON. The project of a task is the list it
+// belongs to, and tasks without a project go to the inbox list. The uuid of a
+// task is kept so that it can be exported back to Taskwarrior, and tasks whose
+// UUID is not a valid uuid (e.g. the UID of a calendar task) get one derived
+// from it, so that exporting them twice does not duplicate them. Taskwarrior has
+// no subtasks, so they are exported as tasks of their own, and recurrences are
+// not kept. Deleted tasks and the templates of recurring tasks are not imported.
+
+const taskwarriorTimeLayout = "20060102T150405Z"
+
+type taskwarriorTask struct {
+	UUID        string                  `json:"uuid,omitempty"`
+	Description string                  `json:"description"`
+	Status      string                  `json:"status"`
+	Project     string                  `json:"project,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Priority    string                  `json:"priority,omitempty"`
+	Due         string                  `json:"due,omitempty"`
+	Scheduled   string                  `json:"scheduled,omitempty"`
+	Entry       string                  `json:"entry,omitempty"`
+	Modified    string                  `json:"modified,omitempty"`
+	End         string                  `json:"end,omitempty"`
+	Annotations []taskwarriorAnnotation `json:"annotations,omitempty"`
+}
+
+type taskwarriorAnnotation struct {
+	Entry       string `json:"entry"`
+	Description string `json:"description"`
+}
+
+var taskwarriorPriorities = map[string]string{"H": "high", "M": "medium", "L": "low"}
+
+// Check whether JSON content is a Taskwarrior export rather than listly lists.
+// Taskwarrior writes an array of tasks, or one task per line in older versions.
+func isTaskwarriorJSON(content []byte) bool {
+	content = bytes.TrimSpace(content)
+	if bytes.HasPrefix(content, []byte("{")) {
+		return true
+	}
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(content, &objects); err != nil || len(objects) == 0 {
+		return false
+	}
+	_, hasTitle := objects[0]["title"]
+	_, hasStatus := objects[0]["status"]
+	return hasStatus && !hasTitle
+}
+
+func taskwarriorToDTOs(content []byte) ([]listDTO, error) {
+	var twTasks []taskwarriorTask
+	dec := json.NewDecoder(bytes.NewReader(content))
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("[")) {
+		if err := dec.Decode(&twTasks); err != nil {
+			return nil, err
+		}
+	} else {
+		for {
+			var twTask taskwarriorTask
+			err := dec.Decode(&twTask)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			twTasks = append(twTasks, twTask)
+		}
+	}
+
+	var tasks []*flatTask
+	for _, twTask := range twTasks {
+		if twTask.Status == "deleted" || twTask.Status == "recurring" {
+			continue
+		}
+		task, err := taskwarriorToTask(twTask)
+		if err != nil {
+			return nil, fmt.Errorf("task %q: %v", twTask.Description, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return flatTasksToDTOs(tasks)
+}
+
+func taskwarriorToTask(twTask taskwarriorTask) (*flatTask, error) {
+	task := &flatTask{list: twTask.Project}
+	if task.list == "" {
+		task.list = defaultListName
+	}
+	if twTask.Description == "" {
+		return nil, fmt.Errorf("task %s has no description", twTask.UUID)
+	}
+
+	var err error
+	task.dto = taskDTO{
+		Description: twTask.Description,
+		Done:        twTask.Status == "completed",
+		Tags:        twTask.Tags,
+		UUID:        twTask.UUID,
+	}
+	if twTask.Priority != "" {
+		priority, ok := taskwarriorPriorities[twTask.Priority]
+		if !ok {
+			return nil, fmt.Errorf("invalid priority %q - expected H, M or L", twTask.Priority)
+		}
+		task.dto.Priority = priority
+	}
+	fields := []struct {
+		value     string
+		dst       *string
+		allowDate bool
+	}{
+		{twTask.Due, &task.dto.Due, true},
+		{twTask.Scheduled, &task.dto.Scheduled, true},
+		{twTask.Entry, &task.dto.CreatedAt, false},
+		{twTask.Modified, &task.dto.UpdatedAt, false},
+		{twTask.End, &task.dto.CompletedAt, false},
+	}
+	for _, field := range fields {
+		*field.dst, err = parseTaskwarriorTime(field.value, field.allowDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !task.dto.Done {
+		task.dto.CompletedAt = ""
+	}
+
+	notes := make([]string, len(twTask.Annotations))
+	for i, annotation := range twTask.Annotations {
+		notes[i] = annotation.Description
+	}
+	task.dto.Notes = strings.Join(notes, "\n")
+	return task, nil
+}
+
+// Parse a Taskwarrior time into a dto timestamp. If allowDate is set, times at
+// midnight local time become dates, since that is how Taskwarrior stores dates.
+func parseTaskwarriorTime(value string, allowDate bool) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	t, err := time.Parse(taskwarriorTimeLayout, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid time %q - expected e.g. 20250620T093000Z", value)
+	}
+	if allowDate && t.Equal(core.StartOfDay(t)) {
+		return core.FormatDate(t), nil
+	}
+	return t.Format(time.RFC3339), nil
+}
+
+// format a dto date or timestamp as a Taskwarrior time
+func formatTaskwarriorTime(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	t, err := core.ParseDate(value)
+	if err != nil {
+		return "", err
+	}
+	return t.UTC().Format(taskwarriorTimeLayout), nil
+}
+
+func dtosToTaskwarrior(dtos []listDTO, now time.Time) ([]byte, error) {
+	twTasks := []taskwarriorTask{}
+	for _, dto := range dtos {
+		var err error
+		twTasks, err = appendTaskwarriorTasks(twTasks, dto.Title, dto.Tasks, now)
+		if err != nil {
+			return nil, fmt.Errorf("list %q: %v", dto.Title, err)
+		}
+	}
+	return json.MarshalIndent(twTasks, "", "  ")
+}
+
+// append the tasks and their subtasks to twTasks in the order they appear in the list
+func appendTaskwarriorTasks(twTasks []taskwarriorTask, list string, dtos []taskDTO, now time.Time) ([]taskwarriorTask, error) {
+	for _, dto := range dtos {
+		twTask := taskwarriorTask{
+			UUID:        taskwarriorUUID(dto.UUID),
+			Description: dto.Description,
+			Status:      "pending",
+		}
+		if list != defaultListName {
+			twTask.Project = list
+		}
+		if dto.Done {
+			twTask.Status = "completed"
+		}
+		for _, tag := range dto.Tags {
+			twTask.Tags = append(twTask.Tags, strings.TrimPrefix(core.NormalizeTag(tag), "#"))
+		}
+		switch dto.Priority {
+		case "urgent", "high":
+			twTask.Priority = "H"
+		case "medium":
+			twTask.Priority = "M"
+		case "low":
+			twTask.Priority = "L"
+		}
+
+		fields := []struct {
+			value string
+			dst   *string
+		}{
+			{dto.Due, &twTask.Due},
+			{dto.Scheduled, &twTask.Scheduled},
+			{dto.CreatedAt, &twTask.Entry},
+			{dto.UpdatedAt, &twTask.Modified},
+			{dto.CompletedAt, &twTask.End},
+		}
+		for _, field := range fields {
+			var err error
+			*field.dst, err = formatTaskwarriorTime(field.value)
+			if err != nil {
+				return nil, fmt.Errorf("task %q: %v", dto.Description, err)
+			}
+		}
+		// Taskwarrior needs to know when tasks were created and completed
+		if twTask.Entry == "" {
+			twTask.Entry = now.UTC().Format(taskwarriorTimeLayout)
+		}
+		if dto.Done && twTask.End == "" {
+			twTask.End = max(twTask.Modified, twTask.Entry)
+		}
+		if dto.Notes != "" {
+			entry := max(twTask.Modified, twTask.Entry)
+			twTask.Annotations = []taskwarriorAnnotation{{Entry: entry, Description: dto.Notes}}
+		}
+
+		twTasks = append(twTasks, twTask)
+		var err error
+		twTasks, err = appendTaskwarriorTasks(twTasks, list, dto.Tasks, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return twTasks, nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Taskwarrior needs a uuid for every task. Other identifiers are turned into a
+// uuid made from their SHA-1 hash, which is the same every time.
+func taskwarriorUUID(id string) string {
+	switch {
+	case id == "":
+		return core.NewUUID()
+	case uuidPattern.MatchString(id):
+		return strings.ToLower(id)
+	}
+	b := sha1.Sum([]byte(id))
+	b[6] = b[6]&0x0f | 0x50
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskwarriorUUID(t *testing.T) {
+	require.Equal(t, "9f2f1c5e-3c1b-4d8e-8a4e-5d1c2b3a4f5e", taskwarriorUUID("9F2F1C5E-3C1B-4D8E-8A4E-5D1C2B3A4F5E"))
+
+	// other identifiers always give the same valid uuid
+	derived := taskwarriorUUID("event-1@calendar.example.com")
+	require.Regexp(t, uuidPattern, derived)
+	require.Equal(t, derived, taskwarriorUUID("event-1@calendar.example.com"))
+	require.NotEqual(t, derived, taskwarriorUUID("event-2@calendar.example.com"))
+
+	require.Regexp(t, uuidPattern, taskwarriorUUID(""))
+}
+
+func TestTaskwarriorRoundTrip(t *testing.T) {
+	dtos := []listDTO{
+		{Title: "web", Tasks: []taskDTO{{
+			Description: "deploy",
+			Priority:    "high",
+			Tags:        []string{"backend"},
+			Due:         "2025-06-20",
+			Notes:       "after review",
+			CreatedAt:   "2025-06-01T09:30:00Z",
+			UpdatedAt:   "2025-06-02T09:30:00Z",
+			UUID:        "9f2f1c5e-3c1b-4d8e-8a4e-5d1c2b3a4f5e",
+		}}},
+		{Title: defaultListName, Tasks: []taskDTO{{
+			Description: "call mom",
+			Done:        true,
+			CreatedAt:   "2025-06-01T09:30:00Z",
+			UpdatedAt:   "2025-06-01T09:30:00Z",
+			CompletedAt: "2025-06-01T09:30:00Z",
+			UUID:        "0c6b8f3e-7a55-4f0e-9a57-2b8c1e4d5f6a",
+		}}},
+	}
+	now := time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)
+	first, err := dtosToTaskwarrior(dtos, now)
+	require.NoError(t, err)
+	got, err := taskwarriorToDTOs(first)
+	require.NoError(t, err)
+	require.Equal(t, dtos, got, string(first))
+
+	// exporting again gives the same uuids, so "task import" updates the tasks instead of adding them again
+	second, err := dtosToTaskwarrior(got, now)
+	require.NoError(t, err)
+	require.Equal(t, string(first), string(second))
+}
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jlz22/listly/core"
+)
+
+// todo.txt files (see https://github.com/todotxt/todo.txt). Each line is a task
+// and its first +project is the list it belongs to, so one file can hold many
+// lists. Lines without a project go to the inbox list. @contexts are kept in the
+// description where listly already treats them as tags. The due:, t: (scheduled),
+// rec: and pri: (priority of completed tasks) extensions are understood, and
+// subtasks are written with id: on the parent and p: on its subtasks. Notes and
+// explicit tags are written with the note: and tag: extensions, with spaces,
+// newlines and % escaped as in URLs.
+
+var todoTxtPriority = regexp.MustCompile(`^\(([A-Z])\)$`)
+
+// a todo.txt rec: value or an org-mode repeater, e.g. 1w, +3d or .+1m
+var repeaterPattern = regexp.MustCompile(`^(?:\+|\+\+|\.\+)?(\d*)([dwm])$`)
+
+// todo.txt and org-mode priorities from A down, letters after D are read as low
+var letterPriorities = []string{"urgent", "high", "medium", "low"}
+
+func todoTxtToDTOs(content []byte) ([]listDTO, error) {
+	var tasks []*flatTask
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		task, err := parseTodoTxtLine(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return flatTasksToDTOs(tasks)
+}
+
+// parse a single non-empty line of a todo.txt file
+func parseTodoTxtLine(line string) (*flatTask, error) {
+	task := &flatTask{}
+	words := strings.Fields(line)
+
+	if len(words) > 0 && words[0] == "x" {
+		task.dto.Done = true
+		words = words[1:]
+	}
+	if len(words) > 0 {
+		if parts := todoTxtPriority.FindStringSubmatch(words[0]); parts != nil {
+			task.dto.Priority = parseLetterPriority(parts[1])
+			words = words[1:]
+		}
+	}
+
+	// a completed task may have a completion date before its creation date
+	maxDates := 1
+	if task.dto.Done {
+		maxDates = 2
+	}
+	var dates []string
+	for len(words) > 0 && len(dates) < maxDates {
+		date, err := time.ParseInLocation(core.DateLayout, words[0], time.Local)
+		if err != nil {
+			break
+		}
+		dates = append(dates, date.Format(time.RFC3339))
+		words = words[1:]
+	}
+	if task.dto.Done && len(dates) > 0 {
+		task.dto.CompletedAt = dates[0]
+		dates = dates[1:]
+	}
+	if len(dates) > 0 {
+		task.dto.CreatedAt = dates[0]
+	}
+
+	var description []string
+	for _, word := range words {
+		if strings.HasPrefix(word, "+") && len(word) > 1 && task.list == "" {
+			task.list = word[1:]
+			continue
+		}
+		key, value, ok := strings.Cut(word, ":")
+		if !ok || value == "" {
+			description = append(description, word)
+			continue
+		}
+		switch key {
+		case "due":
+			task.dto.Due = value
+		case "t":
+			task.dto.Scheduled = value
+		case "id":
+			task.id = value
+		case "p":
+			task.parent = value
+		case "rec":
+			recur, ok := parseRepeater(value)
+			if !ok {
+				description = append(description, word)
+				break
+			}
+			task.dto.Recur = recur
+		case "pri":
+			if len(value) != 1 || value[0] < 'A' || value[0] > 'Z' || task.dto.Priority != "" {
+				description = append(description, word)
+				break
+			}
+			task.dto.Priority = parseLetterPriority(value)
+		case "note", "tag":
+			value, err := url.PathUnescape(value)
+			if err != nil {
+				description = append(description, word)
+				break
+			}
+			if key == "tag" {
+				task.dto.Tags = append(task.dto.Tags, value)
+			} else {
+				task.dto.Notes = value
+			}
+		default:
+			description = append(description, word)
+		}
+	}
+
+	task.dto.Description = strings.Join(description, " ")
+	if task.dto.Description == "" {
+		return nil, fmt.Errorf("task has no description")
+	}
+	if task.list == "" {
+		task.list = defaultListName
+	}
+	return task, nil
+}
+
+func parseLetterPriority(letter string) string {
+	return letterPriorities[min(int(letter[0]-'A'), len(letterPriorities)-1)]
+}
+
+func formatLetterPriority(priority string) string {
+	for i, name := range letterPriorities {
+		if name == priority {
+			return string(rune('A' + i))
+		}
+	}
+	return ""
+}
+
+// convert a todo.txt rec: value or an org-mode repeater such as 1w or +3d into a listly recurrence
+func parseRepeater(value string) (string, bool) {
+	parts := repeaterPattern.FindStringSubmatch(value)
+	if parts == nil {
+		return "", false
+	}
+	n := 1
+	if parts[1] != "" {
+		var err error
+		n, err = strconv.Atoi(parts[1])
+		if err != nil || n < 1 {
+			return "", false
+		}
+	}
+	unit := map[string]string{"d": "day", "w": "week", "m": "month"}[parts[2]]
+	return fmt.Sprintf("every %d %ss", n, unit), true
+}
+
+// convert a listly recurrence into a repeater such as 1w. Weekdays cannot be written.
+func formatRepeater(recur string) string {
+	r, err := core.ParseRecurrence(recur)
+	if err != nil || !r.IsSet() {
+		return ""
+	}
+	unit := map[core.Frequency]string{core.FrequencyDaily: "d", core.FrequencyWeekly: "w", core.FrequencyMonthly: "m"}[r.Frequency]
+	return fmt.Sprintf("%d%s", max(1, r.Interval), unit)
+}
+
+func dtosToTodoTxt(dtos []listDTO) ([]byte, error) {
+	var b strings.Builder
+	nextId := 1
+	for _, dto := range dtos {
+		if strings.ContainsAny(dto.Title, " \t") {
+			return nil, fmt.Errorf("list %q cannot be written to todo.txt because +projects cannot contain spaces", dto.Title)
+		}
+		err := writeTodoTxtTasks(&b, dto.Title, dto.Tasks, "", &nextId)
+		if err != nil {
+			return nil, fmt.Errorf("list %q: %v", dto.Title, err)
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+func writeTodoTxtTasks(b *strings.Builder, list string, dtos []taskDTO, parent string, nextId *int) error {
+	for _, dto := range dtos {
+		var words []string
+		if dto.Done {
+			words = append(words, "x")
+		}
+		priority := formatLetterPriority(dto.Priority)
+		if priority != "" && !dto.Done {
+			words = append(words, "("+priority+")")
+		}
+
+		created, err := formatTodoTxtTimestamp(dto.CreatedAt)
+		if err != nil {
+			return err
+		}
+		completed, err := formatTodoTxtTimestamp(dto.CompletedAt)
+		if err != nil {
+			return err
+		}
+		if dto.Done && completed != "" {
+			words = append(words, completed)
+		}
+		if created != "" && (!dto.Done || completed != "") {
+			words = append(words, created)
+		}
+
+		// the project goes first if the description could be mistaken for it or for the start of the line
+		projectFirst := todoTxtNeedsProjectFirst(dto.Description)
+		if projectFirst {
+			words = append(words, "+"+list)
+		}
+		words = append(words, dto.Description)
+		if list != defaultListName && !projectFirst {
+			words = append(words, "+"+list)
+		}
+		for _, tag := range dto.Tags {
+			words = append(words, "tag:"+escapeTodoTxtValue(tag))
+		}
+		if due := formatTodoTxtDate(dto.Due); due != "" {
+			words = append(words, "due:"+due)
+		}
+		if scheduled := formatTodoTxtDate(dto.Scheduled); scheduled != "" {
+			words = append(words, "t:"+scheduled)
+		}
+		if dto.Recur != "" {
+			if r, err := core.ParseRecurrence(dto.Recur); err == nil && len(r.Weekdays) > 0 {
+				return fmt.Errorf("task %q repeats %s, but todo.txt recurrences cannot name weekdays - export to JSON, YAML, iCalendar or Org instead", dto.Description, dto.Recur)
+			}
+		}
+		if recur := formatRepeater(dto.Recur); recur != "" {
+			words = append(words, "rec:"+recur)
+		}
+		if priority != "" && dto.Done {
+			words = append(words, "pri:"+priority)
+		}
+		var id string
+		if len(dto.Tasks) > 0 {
+			id = strconv.Itoa(*nextId)
+			*nextId++
+			words = append(words, "id:"+id)
+		}
+		if parent != "" {
+			words = append(words, "p:"+parent)
+		}
+		if dto.Notes != "" {
+			words = append(words, "note:"+escapeTodoTxtValue(dto.Notes))
+		}
+
+		b.WriteString(strings.Join(words, " ") + "\n")
+		err = writeTodoTxtTasks(b, list, dto.Tasks, id, nextId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Whether a description would be read back wrongly if the +project came after
+// it: it starts like a completed task, a priority or a date, or it contains a
+// word that would be taken for the project.
+func todoTxtNeedsProjectFirst(description string) bool {
+	words := strings.Fields(description)
+	if len(words) == 0 {
+		return false
+	}
+	if _, err := time.Parse(core.DateLayout, words[0]); err == nil || words[0] == "x" || todoTxtPriority.MatchString(words[0]) {
+		return true
+	}
+	for _, word := range words {
+		if strings.HasPrefix(word, "+") && len(word) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// escape a note: or tag: value so that it is a single word
+func escapeTodoTxtValue(value string) string {
+	return strings.NewReplacer("%", "%25", " ", "%20", "\t", "%09", "\n", "%0A", "\r", "%0D").Replace(value)
+}
+
+// format a creation or completion time of a dto as a todo.txt date
+func formatTodoTxtTimestamp(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	t, err := parseDTOTimestamp(s)
+	if err != nil {
+		return "", err
+	}
+	return core.FormatDate(t), nil
+}
+
+// format a due or scheduled date of a dto as a todo.txt date, dropping the time of day
+func formatTodoTxtDate(s string) string {
+	t, err := parseDTODate(s)
+	if err != nil {
+		return ""
+	}
+	return core.FormatDate(t)
+}
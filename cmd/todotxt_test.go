@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// a dto timestamp of midnight local time, which is all todo.txt keeps of a timestamp
+func todoTxtDay(day int) string {
+	return time.Date(2025, 6, day, 0, 0, 0, 0, time.Local).Format(time.RFC3339)
+}
+
+func TestParseTodoTxtLine(t *testing.T) {
+	tests := []struct {
+		line string
+		list string
+		want taskDTO
+	}{
+		{
+			line: "(A) 2025-06-01 call the plumber @phone +house due:2025-06-20",
+			list: "house",
+			want: taskDTO{Description: "call the plumber @phone", Priority: "urgent", CreatedAt: todoTxtDay(1), Due: "2025-06-20"},
+		},
+		{
+			line: "x 2025-06-03 2025-06-01 buy paint +house +paint pri:B",
+			list: "house",
+			want: taskDTO{Description: "buy paint +paint", Done: true, Priority: "high", CompletedAt: todoTxtDay(3), CreatedAt: todoTxtDay(1)},
+		},
+		{
+			line: "water plants t:2025-06-02 rec:+3d meet at 10:30",
+			list: defaultListName,
+			want: taskDTO{Description: "water plants meet at 10:30", Scheduled: "2025-06-02", Recur: "every 3 days"},
+		},
+		{
+			line: "(E) read tag:%23books note:chapter%201%0Aand%202 rec:yearly",
+			list: defaultListName,
+			want: taskDTO{Description: "read rec:yearly", Priority: "low", Tags: []string{"#books"}, Notes: "chapter 1\nand 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			task, err := parseTodoTxtLine(tt.line)
+			require.NoError(t, err)
+			require.Equal(t, tt.list, task.list)
+			require.Equal(t, tt.want, task.dto)
+		})
+	}
+
+	_, err := parseTodoTxtLine("x 2025-06-03 +house due:2025-06-20")
+	require.Error(t, err)
+}
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	dtos := []listDTO{
+		{Title: defaultListName, Tasks: []taskDTO{
+			{Description: "x marks the spot"},
+			{Description: "vote +1 on the proposal", Tags: []string{"#work", "@alice"}},
+		}},
+		{Title: "house", Tasks: []taskDTO{
+			{
+				Description: "paint the fence",
+				Priority:    "medium",
+				Due:         "2025-06-20",
+				Scheduled:   "2025-06-10",
+				Recur:       "every 2 weeks",
+				Notes:       "white, not 100% sure\n\n  second coat",
+				CreatedAt:   todoTxtDay(1),
+				Tasks: []taskDTO{
+					{Description: "buy paint", Done: true, Priority: "high", CreatedAt: todoTxtDay(1), CompletedAt: todoTxtDay(3)},
+					{Description: "2025-06-01 is the deadline"},
+				},
+			},
+		}},
+	}
+	content, err := dtosToTodoTxt(dtos)
+	require.NoError(t, err)
+	got, err := todoTxtToDTOs(content)
+	require.NoError(t, err)
+	require.Equal(t, dtos, got, string(content))
+}
+
+func TestDtosToTodoTxt_Errors(t *testing.T) {
+	for _, dto := range []listDTO{
+		{Title: "two words", Tasks: []taskDTO{{Description: "a"}}},
+		{Title: "gym", Tasks: []taskDTO{{Description: "lift", Recur: "weekly on mon,thu"}}},
+	} {
+		_, err := dtosToTodoTxt([]listDTO{dto})
+		require.Error(t, err, dto.Title)
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jlz22/listly/core"
+	"github.com/spf13/cobra"
+)
+
+var trashOlderThan string
+
+var TrashCmd = &cobra.Command{
+	Use:   "trash [command]",
+	Short: "Manage deleted lists and cleaned tasks",
+}
+
+var TrashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the deleted lists and cleaned tasks in the trash, most recently deleted first.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return core.WithDefaultDB(func(db *core.DB) error {
+			items, err := db.GetTrash()
+			if err != nil {
+				return fmt.Errorf("could not retrieve the trash due to the following error\n\t %v", err)
+			}
+			if len(items) == 0 {
+				fmt.Println("The trash is empty.")
+				return nil
+			}
+
+			nameWidth := len("List")
+			for _, item := range items {
+				nameWidth = max(nameWidth, len(item.List.Info.Name))
+			}
+			idWidth := max(len("ID"), len(strconv.Itoa(items[0].Id)))
+			fmt.Printf("%-*s  %-*s  %-*s  %s\n", idWidth, "ID", len(core.TimestampLayout), "Deleted", nameWidth, "List", "Contents")
+			for _, item := range items {
+				fmt.Printf("%-*d  %s  %-*s  %s\n", idWidth, item.Id, formatTimestamp(item.Time), nameWidth, item.List.Info.Name, item.Summary())
+			}
+			return nil
+		})
+	},
+}
+
+var TrashRestoreCmd = &cobra.Command{
+	Use:   "restore <list name | id>",
+	Short: "Restore a deleted list, or put cleaned tasks back into their list. A list name restores the most recent item for that list.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return core.WithDefaultDB(func(db *core.DB) error {
+			items, err := db.GetTrash()
+			if err != nil {
+				return fmt.Errorf("could not retrieve the trash due to the following error\n\t %v", err)
+			}
+			id, err := findTrashItem(items, args[0])
+			if err != nil {
+				return err
+			}
+
+			item, err := db.RestoreTrashItem(id)
+			if err != nil {
+				return fmt.Errorf("could not restore %s due to the following error\n\t %v", args[0], err)
+			}
+			if item.Kind == core.TrashedList {
+				core.Success(fmt.Sprintf("Restored the list '%s'", item.List.Info.Name))
+			} else {
+				core.Success(fmt.Sprintf("Restored %d cleaned tasks to '%s'", len(item.List.Tasks), item.List.Info.Name))
+			}
+			return nil
+		})
+	},
+}
+
+var TrashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently delete everything in the trash, or only the items older than --older-than.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var olderThan time.Duration
+		if trashOlderThan != "" {
+			var err error
+			olderThan, err = core.ParseAge(trashOlderThan)
+			if err != nil {
+				return err
+			}
+		}
+
+		return core.WithDefaultDB(func(db *core.DB) error {
+			numDeleted, err := db.EmptyTrash(olderThan)
+			if err != nil {
+				return fmt.Errorf("could not empty the trash due to the following error\n\t %v", err)
+			}
+			core.Success(fmt.Sprintf("Permanently deleted %d items from the trash.", numDeleted))
+			return nil
+		})
+	},
+}
+
+func setUpTrash() {
+	RootCmd.AddCommand(TrashCmd)
+	TrashCmd.AddCommand(TrashListCmd)
+	TrashCmd.AddCommand(TrashRestoreCmd)
+	TrashCmd.AddCommand(TrashEmptyCmd)
+	TrashEmptyCmd.Flags().StringVar(&trashOlderThan, "older-than", "", "Only delete items that have been in the trash for longer than this, e.g. 30d, 2w or 12h")
+}
+
+// Find the trash item for a list name, preferring the most recent one, or by id.
+func findTrashItem(items []core.TrashItem, arg string) (int, error) {
+	for _, item := range items {
+		if item.List.Info.Name == arg {
+			return item.Id, nil
+		}
+	}
+	if id, err := strconv.Atoi(arg); err == nil {
+		for _, item := range items {
+			if item.Id == id {
+				return id, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("nothing named %q in the trash - see `listly trash list`", arg)
+}
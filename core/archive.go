@@ -0,0 +1,76 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// a completed task kept in the archive of its list
+type ArchivedTask struct {
+	Task
+	ArchivedAt time.Time
+}
+
+// Move the given tasks into the archive bucket of a list.
+func addToArchive(listBucket *bolt.Bucket, tasks []*Task) error {
+	archive, err := listBucket.CreateBucketIfNotExists([]byte("archive"))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, task := range tasks {
+		if err := saveTask(archive, *task); err != nil {
+			return err
+		}
+		if err := putTime(archive.Bucket(itob(task.Id)), "archived", now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read the archive of a list, most recently completed first. Only tasks completed
+// at or after since are returned, unless since is the zero time.
+func getArchive(listBucket *bolt.Bucket, since time.Time) ([]ArchivedTask, error) {
+	tasks := []ArchivedTask{}
+	archive := listBucket.Bucket([]byte("archive"))
+	if archive == nil {
+		return tasks, nil
+	}
+
+	err := archive.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return nil
+		}
+		taskBucket := archive.Bucket(k)
+		task, err := getTask(taskBucket)
+		if err != nil {
+			return fmt.Errorf("failed to read archived task %d: %w", btoi(k), err)
+		}
+		if !since.IsZero() && task.CompletedAt.Before(since) {
+			return nil
+		}
+		tasks = append(tasks, ArchivedTask{Task: task, ArchivedAt: getTime(taskBucket, "archived")})
+		return nil
+	})
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].CompletedAt.After(tasks[j].CompletedAt)
+	})
+	return tasks, err
+}
+
+// copy the archive bucket of src, if any, into dst
+func copyArchive(src, dst *bolt.Bucket) error {
+	archive := src.Bucket([]byte("archive"))
+	if archive == nil {
+		return nil
+	}
+	dstArchive, err := dst.CreateBucketIfNotExists([]byte("archive"))
+	if err != nil {
+		return err
+	}
+	return copyBucket(archive, dstArchive)
+}
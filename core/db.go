@@ -3,6 +3,8 @@ package core
 import (
 	"fmt"
 	"os"
+	"sort"
+	"time"
 
 	bolt "go.etcd.io/bbolt"
 )
@@ -22,20 +24,59 @@ import (
 // 					"name": "string",
 // 					"numDone": int,
 // 					"numPending": int,
-// 					"numTasks": int
+// 					"numTasks": int,
+// 					"nextId": int (id of the next new task),
+// 					"created": int (unix seconds, optional),
+// 					"updated": int (unix seconds, optional)
 // 				},
+// 				"archive": {
+// 					"taskId": { same keys as a task, plus "archived": int (unix seconds) },
+// 					...
+// 				} (completed tasks moved here by `listly clean --archive`, optional),
 // 				"data": {
 // 					"taskIds": []int,
 // 					"tasks": {
 // 						"taskId": {
+// 							"id": int,
+// 							"parent": int (id of the parent task, optional),
 // 							"description": "string",
-// 							"done": false
+// 							"done": false,
+// 							"priority": int (0 = none ... 4 = urgent),
+// 							"tags": "newline separated tags (optional)",
+// 							"due": int (unix seconds, optional),
+// 							"scheduled": int (unix seconds, optional),
+// 							"recur": "recurrence rule, e.g. weekly on mon (optional)",
+// 							"next": int (id of the spawned next occurrence, optional),
+// 							"notes": "free-form text (optional)",
+// 							"uuid": "string (identifies the task in other apps, set for every task)",
+// 							"created": int (unix seconds, optional),
+// 							"updated": int (unix seconds, optional),
+// 							"completed": int (unix seconds, optional)
 // 						},
 // 						...
 // 					}
 // 				}
 // 			},
 // 			...
+// 		},
+// 		"journal": {
+// 			"entryId": {
+// 				"time": int (unix seconds),
+// 				"list": "string",
+// 				"op": "save | clean | archive | rename | delete | restore",
+// 				"before": { "info": {...}, "data": {...} } (state before the change, optional),
+// 				"after": { "info": {...}, "data": {...} } (state after the change, optional)
+// 			},
+// 			...
+// 		},
+// 		"trash": {
+// 			"itemId": {
+// 				"time": int (unix seconds),
+// 				"kind": "list | tasks",
+// 				"content": { "info": {...}, "data": {...} } (the deleted list or the cleaned tasks),
+// 				"archive": {...} (archive of a deleted list, optional)
+// 			},
+// 			...
 // 		}
 // ------------------------------------------------------
 
@@ -77,7 +118,11 @@ func InitDB(path string) (*DB, error) {
 			return err
 		}
 
-		_, err = tx.CreateBucketIfNotExists([]byte("lists"))
+		allLists, err := tx.CreateBucketIfNotExists([]byte("lists"))
+		if err != nil {
+			return err
+		}
+		err = assignMissingUUIDs(allLists)
 		if err != nil {
 			return err
 		}
@@ -87,6 +132,16 @@ func InitDB(path string) (*DB, error) {
 			return err
 		}
 
+		_, err = tx.CreateBucketIfNotExists([]byte("journal"))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateBucketIfNotExists([]byte("trash"))
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -150,39 +205,22 @@ func (db *DB) GetInfo() (map[string]ListInfo, error) {
 
 // get a specific list by name
 func (db *DB) GetList(name string) (List, error) {
-	list := NewList(name)
+	var list List
 	err := db.BoltDB.View(func(tx *bolt.Tx) error {
 		allLists := tx.Bucket([]byte("lists"))
 		if allLists == nil {
 			return fmt.Errorf("lists bucket not found - likely issue with database initialization")
 		}
 
-		_, dataBucket, err := openList(allLists, name, false)
+		infoBucket, dataBucket, err := openList(allLists, name, false)
 		if err != nil {
 			return fmt.Errorf("failed to open list %s: %w", name, err)
 		}
 
-		data, err := getData(dataBucket)
+		list, err = loadList(infoBucket, dataBucket)
 		if err != nil {
-			return fmt.Errorf("failed to get data for list %s: %w", name, err)
-		}
-
-		list.Tasks = data.Tasks
-		list.TaskIds = data.TaskIds
-		list.UsedIds = data.UsedIds
-
-		// align list info with data
-		list.Info.NumTasks = len(list.Tasks)
-		list.Info.NumDone = 0
-		list.Info.NumPending = 0
-		for _, task := range list.Tasks {
-			if task.Done {
-				list.Info.NumDone++
-			} else {
-				list.Info.NumPending++
-			}
+			return fmt.Errorf("failed to load list %s: %w", name, err)
 		}
-
 		return nil
 	})
 	return list, err
@@ -208,7 +246,7 @@ func (db *DB) SetCurrentListName(name string) error {
 	})
 }
 
-// save the given list
+// save the given list and record the change in the journal
 func (db *DB) SaveList(list List) error {
 	return db.BoltDB.Update(func(tx *bolt.Tx) error {
 		rootBucket := tx.Bucket([]byte("lists"))
@@ -216,36 +254,14 @@ func (db *DB) SaveList(list List) error {
 			return fmt.Errorf("lists bucket not found - likely issue with database initialization")
 		}
 
-		infoBucket, dataBucket, err := openList(rootBucket, list.Info.Name, true)
+		before, err := readList(rootBucket, list.Info.Name)
 		if err != nil {
 			return err
 		}
-
-		// align list info with data
-		list.Info.NumTasks = len(list.Tasks)
-		list.Info.NumDone = 0
-		list.Info.NumPending = 0
-		for _, task := range list.Tasks {
-			if task.Done {
-				list.Info.NumDone++
-			} else {
-				list.Info.NumPending++
-			}
-		}
-
-		// save info into meta data bucket
-		err = saveInfo(infoBucket, list.Info)
-		if err != nil {
+		if err := writeList(rootBucket, list.Info.Name, list); err != nil {
 			return err
 		}
-
-		// save data into data bucket
-		err = saveData(dataBucket, list)
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return addJournalEntry(tx, OpSave, before, &list)
 	})
 }
 
@@ -254,63 +270,79 @@ func (db *DB) SaveList(list List) error {
 // recreate the bucket with the new name.
 func (db *DB) RenameList(oldName, newName string) error {
 	return db.BoltDB.Update(func(tx *bolt.Tx) error {
-		allLists := tx.Bucket([]byte("lists"))
-		if allLists == nil {
-			return fmt.Errorf("lists bucket not found")
-		}
+		return renameList(tx, oldName, newName)
+	})
+}
 
-		// Get the old list bucket
-		oldBucket := allLists.Bucket([]byte(oldName))
-		if oldBucket == nil {
-			return fmt.Errorf("old list %s not found", oldName)
-		}
+func renameList(tx *bolt.Tx, oldName, newName string) error {
+	allLists := tx.Bucket([]byte("lists"))
+	if allLists == nil {
+		return fmt.Errorf("lists bucket not found")
+	}
 
-		// Create new bucket with newName
-		newBucket, err := allLists.CreateBucket([]byte(newName))
-		if err != nil {
-			return fmt.Errorf("could not create new bucket %s due to the following error\n\t %w", newName, err)
-		}
+	// Get the old list bucket
+	oldBucket := allLists.Bucket([]byte(oldName))
+	if oldBucket == nil {
+		return fmt.Errorf("old list %s not found", oldName)
+	}
+	before, err := readList(allLists, oldName)
+	if err != nil {
+		return err
+	}
 
-		// Recursively copy all keys/sub-buckets
-		if err = copyBucket(oldBucket, newBucket); err != nil {
-			return fmt.Errorf("could not copy bucket %s to %s due to the following error\n\t %w", oldName, newName, err)
-		}
+	// Create new bucket with newName
+	newBucket, err := allLists.CreateBucket([]byte(newName))
+	if err != nil {
+		return fmt.Errorf("could not create new bucket %s due to the following error\n\t %w", newName, err)
+	}
 
-		// Delete old bucket
-		err = allLists.DeleteBucket([]byte(oldName))
-		if err != nil {
-			return err
-		}
+	// Recursively copy all keys/sub-buckets
+	if err = copyBucket(oldBucket, newBucket); err != nil {
+		return fmt.Errorf("could not copy bucket %s to %s due to the following error\n\t %w", oldName, newName, err)
+	}
 
-		// update info in the new bucket
-		listBucket := allLists.Bucket([]byte(newName))
-		if listBucket == nil {
-			return fmt.Errorf("list bucket %s not found", newName)
-		}
-		infoBucket := listBucket.Bucket([]byte("info"))
-		if infoBucket == nil {
-			return fmt.Errorf("info bucket not found for list %s", newName)
-		}
-		listInfo, err := getInfo(infoBucket)
-		if err != nil {
-			return err
-		}
-		listInfo.Name = newName
-		err = saveInfo(infoBucket, listInfo)
-		if err != nil {
-			return err
-		}
+	// Delete old bucket
+	err = allLists.DeleteBucket([]byte(oldName))
+	if err != nil {
+		return err
+	}
 
-		// if the list being renamed is the current list, update the current list name
-		currListName := getCurrListName(tx)
-		if currListName == oldName {
-			return setCurrListName(tx, newName)
-		}
-		return nil
-	})
+	// update info in the new bucket
+	listBucket := allLists.Bucket([]byte(newName))
+	if listBucket == nil {
+		return fmt.Errorf("list bucket %s not found", newName)
+	}
+	infoBucket := listBucket.Bucket([]byte("info"))
+	if infoBucket == nil {
+		return fmt.Errorf("info bucket not found for list %s", newName)
+	}
+	listInfo, err := getInfo(infoBucket)
+	if err != nil {
+		return err
+	}
+	listInfo.Name = newName
+	listInfo.UpdatedAt = time.Now()
+	err = saveInfo(infoBucket, listInfo)
+	if err != nil {
+		return err
+	}
+	after, err := readList(allLists, newName)
+	if err != nil {
+		return err
+	}
+	if err := addJournalEntry(tx, OpRename, before, after); err != nil {
+		return err
+	}
+
+	// if the list being renamed is the current list, update the current list name
+	currListName := getCurrListName(tx)
+	if currListName == oldName {
+		return setCurrListName(tx, newName)
+	}
+	return nil
 }
 
-// remove the list with the given name
+// move the lists with the given names to the trash, recording them in the journal
 func (db *DB) DeleteLists(names []string) error {
 	return db.BoltDB.Update(func(tx *bolt.Tx) error {
 		allLists := tx.Bucket([]byte("lists"))
@@ -323,13 +355,15 @@ func (db *DB) DeleteLists(names []string) error {
 			if name == currListName {
 				setCurrListName(tx, "")
 			}
-			allLists.DeleteBucket([]byte(name))
+			if err := deleteList(tx, allLists, name); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
 }
 
-// remove all lists
+// move all lists to the trash, recording them in the journal
 func (db *DB) DeleteAllLists() error {
 	return db.BoltDB.Update(func(tx *bolt.Tx) error {
 		allLists := tx.Bucket([]byte("lists"))
@@ -338,14 +372,54 @@ func (db *DB) DeleteAllLists() error {
 		}
 
 		setCurrListName(tx, "")
-		return allLists.ForEach(func(k, v []byte) error {
-			return allLists.DeleteBucket(k)
+		names := []string{}
+		err := allLists.ForEach(func(k, v []byte) error {
+			names = append(names, string(k))
+			return nil
 		})
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err := deleteList(tx, allLists, name); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
 
-// Clean up completed tasks in the specified lists
-func (db *DB) CleanLists(names []string) (int, error) {
+// Delete a list bucket if it exists, moving its contents to the trash and
+// recording them in the journal. Lists that cannot be read are not deleted,
+// since they could not be restored from the trash, unless their bucket is empty.
+func deleteList(tx *bolt.Tx, allLists *bolt.Bucket, name string) error {
+	bucket := allLists.Bucket([]byte(name))
+	if bucket == nil {
+		return nil
+	}
+	before, err := readList(allLists, name)
+	if err != nil {
+		if k, _ := bucket.Cursor().First(); k == nil {
+			return allLists.DeleteBucket([]byte(name))
+		}
+		return fmt.Errorf("failed to read list %q, so it was not deleted: %v", name, err)
+	}
+	item, err := addToTrash(tx, TrashedList, *before)
+	if err != nil {
+		return err
+	}
+	if err := copyArchive(bucket, item); err != nil {
+		return err
+	}
+	if err := allLists.DeleteBucket([]byte(name)); err != nil {
+		return err
+	}
+	return addJournalEntry(tx, OpDelete, before, nil)
+}
+
+// Clean up completed tasks in the specified lists. Completed tasks are moved to
+// the archive of their list if archive is set, and to the trash otherwise.
+func (db *DB) CleanLists(names []string, archive bool) (int, error) {
 	var totalRemoved int
 	err := db.BoltDB.Update(func(tx *bolt.Tx) error {
 		allBuckets := tx.Bucket([]byte("lists"))
@@ -354,12 +428,7 @@ func (db *DB) CleanLists(names []string) (int, error) {
 		}
 
 		for _, name := range names {
-			listBucket := allBuckets.Bucket([]byte(name))
-			if listBucket == nil {
-				continue // skip if the list does not exist
-			}
-
-			numRemoved, err := cleanList(listBucket)
+			numRemoved, err := cleanList(tx, allBuckets, name, archive)
 			if err != nil {
 				return err
 			}
@@ -370,8 +439,8 @@ func (db *DB) CleanLists(names []string) (int, error) {
 	return totalRemoved, err
 }
 
-// Clean up completed tasks in all lists
-func (db *DB) CleanAllLists() (int, error) {
+// Clean up completed tasks in all lists, see CleanLists
+func (db *DB) CleanAllLists(archive bool) (int, error) {
 	var totalRemoved int
 	err := db.BoltDB.Update(func(tx *bolt.Tx) error {
 		allBuckets := tx.Bucket([]byte("lists"))
@@ -379,25 +448,30 @@ func (db *DB) CleanAllLists() (int, error) {
 			return fmt.Errorf("lists bucket not found")
 		}
 
-		return allBuckets.ForEach(func(k, v []byte) error {
-			listBucket := allBuckets.Bucket(k)
-			if listBucket == nil {
-				return nil // skip if the list does not exist
+		names := []string{}
+		err := allBuckets.ForEach(func(k, v []byte) error {
+			if v == nil {
+				names = append(names, string(k))
 			}
-
-			numRemoved, err := cleanList(listBucket)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			numRemoved, err := cleanList(tx, allBuckets, name, archive)
 			if err != nil {
 				return err
 			}
 			totalRemoved += numRemoved
-			return nil
-		})
+		}
+		return nil
 	})
 	return totalRemoved, err
 }
 
-// Clean up completed tasks in the current list
-func (db *DB) CleanCurrentList() (int, error) {
+// Clean up completed tasks in the current list, see CleanLists
+func (db *DB) CleanCurrentList(archive bool) (int, error) {
 	var totalRemoved int
 	err := db.BoltDB.Update(func(tx *bolt.Tx) error {
 		name := getCurrListName(tx)
@@ -407,11 +481,7 @@ func (db *DB) CleanCurrentList() (int, error) {
 			return fmt.Errorf("lists bucket not found")
 		}
 
-		listBucket := allBuckets.Bucket([]byte(name))
-		if listBucket == nil {
-			return nil // skip if the list does not exist
-		}
-		numRemoved, err := cleanList(listBucket)
+		numRemoved, err := cleanList(tx, allBuckets, name, archive)
 		if err != nil {
 			return err
 		}
@@ -421,6 +491,319 @@ func (db *DB) CleanCurrentList() (int, error) {
 	return totalRemoved, err
 }
 
+// Get the journal entries that changed the list with the given name, newest
+// first. An empty name gives the entries of all lists.
+func (db *DB) GetJournal(listName string) ([]JournalEntry, error) {
+	entries := []JournalEntry{}
+	err := db.BoltDB.View(func(tx *bolt.Tx) error {
+		journal := tx.Bucket([]byte("journal"))
+		if journal == nil {
+			return fmt.Errorf("journal bucket not found - likely issue with database initialization")
+		}
+
+		c := journal.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			if v != nil {
+				continue
+			}
+			entry, err := getJournalEntry(journal.Bucket(k), btoi(k))
+			if err != nil {
+				return err
+			}
+			if listName == "" || entry.Touches(listName) {
+				entries = append(entries, entry)
+			}
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// get a single journal entry by id
+func (db *DB) GetJournalEntry(id int) (JournalEntry, error) {
+	var entry JournalEntry
+	err := db.BoltDB.View(func(tx *bolt.Tx) error {
+		journal := tx.Bucket([]byte("journal"))
+		if journal == nil {
+			return fmt.Errorf("journal bucket not found - likely issue with database initialization")
+		}
+		b := journal.Bucket(itob(id))
+		if b == nil {
+			return fmt.Errorf("journal entry %d not found", id)
+		}
+		var err error
+		entry, err = getJournalEntry(b, id)
+		return err
+	})
+	return entry, err
+}
+
+// Roll a list back to the state it had before the change recorded in the
+// journal entry with the given id. A renamed list gets its old name back if
+// that name is free, and a deleted list is recreated. The restore itself is
+// recorded in the journal, so it can be undone as well.
+func (db *DB) RestoreJournalEntry(id int) (List, error) {
+	var restored List
+	err := db.BoltDB.Update(func(tx *bolt.Tx) error {
+		journal := tx.Bucket([]byte("journal"))
+		if journal == nil {
+			return fmt.Errorf("journal bucket not found - likely issue with database initialization")
+		}
+		b := journal.Bucket(itob(id))
+		if b == nil {
+			return fmt.Errorf("journal entry %d not found", id)
+		}
+		entry, err := getJournalEntry(b, id)
+		if err != nil {
+			return err
+		}
+		if entry.Before == nil {
+			return fmt.Errorf("journal entry %d created list %s, so there is no earlier state to restore - delete the list instead", id, entry.List)
+		}
+		restored = *entry.Before
+
+		allLists := tx.Bucket([]byte("lists"))
+		if allLists == nil {
+			return fmt.Errorf("lists bucket not found")
+		}
+		name := restored.Info.Name
+		if entry.After != nil && entry.After.Info.Name != name &&
+			allLists.Bucket([]byte(entry.After.Info.Name)) != nil && allLists.Bucket([]byte(name)) == nil {
+			if err := renameList(tx, entry.After.Info.Name, name); err != nil {
+				return err
+			}
+		}
+
+		current, err := readList(allLists, name)
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			// keep handing out new ids so that ids stay unique in the journal
+			restored.Info.NextId = max(restored.Info.NextId, current.Info.NextId)
+		}
+		restored.Info.UpdatedAt = time.Now()
+		if err := writeList(allLists, name, restored); err != nil {
+			return err
+		}
+		return addJournalEntry(tx, OpRestore, current, &restored)
+	})
+	return restored, err
+}
+
+// get everything in the trash, most recently deleted first
+func (db *DB) GetTrash() ([]TrashItem, error) {
+	items := []TrashItem{}
+	err := db.BoltDB.View(func(tx *bolt.Tx) error {
+		trash := tx.Bucket([]byte("trash"))
+		if trash == nil {
+			return fmt.Errorf("trash bucket not found - likely issue with database initialization")
+		}
+
+		c := trash.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			if v != nil {
+				continue
+			}
+			item, err := getTrashItem(trash.Bucket(k), btoi(k))
+			if err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return nil
+	})
+	return items, err
+}
+
+// Take the item with the given id out of the trash. A deleted list is recreated
+// unless another list took its name, and cleaned tasks are added back to the end
+// of their list.
+func (db *DB) RestoreTrashItem(id int) (TrashItem, error) {
+	var item TrashItem
+	err := db.BoltDB.Update(func(tx *bolt.Tx) error {
+		trash := tx.Bucket([]byte("trash"))
+		if trash == nil {
+			return fmt.Errorf("trash bucket not found - likely issue with database initialization")
+		}
+		b := trash.Bucket(itob(id))
+		if b == nil {
+			return fmt.Errorf("trash item %d not found", id)
+		}
+		var err error
+		item, err = getTrashItem(b, id)
+		if err != nil {
+			return err
+		}
+
+		allLists := tx.Bucket([]byte("lists"))
+		if allLists == nil {
+			return fmt.Errorf("lists bucket not found")
+		}
+		name := item.List.Info.Name
+		current, err := readList(allLists, name)
+		if err != nil {
+			return err
+		}
+
+		var restored List
+		switch item.Kind {
+		case TrashedList:
+			if current != nil {
+				return fmt.Errorf("a list named %s already exists - rename or delete it first", name)
+			}
+			restored = item.List
+			restored.Info.UpdatedAt = time.Now()
+		case TrashedTasks:
+			if current == nil {
+				return fmt.Errorf("list %s no longer exists - restore it from the trash first", name)
+			}
+			restored = current.Clone()
+			if err := restored.restoreTasks(item.List); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("trash item %d has unknown kind %q", id, item.Kind)
+		}
+
+		if err := writeList(allLists, name, restored); err != nil {
+			return err
+		}
+		if item.Kind == TrashedList {
+			if err := copyArchive(b, allLists.Bucket([]byte(name))); err != nil {
+				return err
+			}
+		}
+		if err := addJournalEntry(tx, OpRestore, current, &restored); err != nil {
+			return err
+		}
+		return trash.DeleteBucket(itob(id))
+	})
+	return item, err
+}
+
+// Permanently delete the items that have been in the trash for longer than the
+// given age, or everything if the age is 0. Returns how many items were deleted.
+func (db *DB) EmptyTrash(olderThan time.Duration) (int, error) {
+	numDeleted := 0
+	err := db.BoltDB.Update(func(tx *bolt.Tx) error {
+		trash := tx.Bucket([]byte("trash"))
+		if trash == nil {
+			return fmt.Errorf("trash bucket not found - likely issue with database initialization")
+		}
+
+		cutoff := time.Now().Add(-olderThan)
+		expired := [][]byte{}
+		err := trash.ForEach(func(k, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			if olderThan == 0 || getTime(trash.Bucket(k), "time").Before(cutoff) {
+				expired = append(expired, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range expired {
+			if err := trash.DeleteBucket(k); err != nil {
+				return err
+			}
+		}
+		numDeleted = len(expired)
+		return nil
+	})
+	return numDeleted, err
+}
+
+// Get the archived tasks of a list, most recently completed first. Only tasks
+// completed at or after since are returned, unless since is the zero time.
+func (db *DB) GetArchive(listName string, since time.Time) ([]ArchivedTask, error) {
+	var tasks []ArchivedTask
+	err := db.BoltDB.View(func(tx *bolt.Tx) error {
+		allLists := tx.Bucket([]byte("lists"))
+		if allLists == nil {
+			return fmt.Errorf("lists bucket not found - likely issue with database initialization")
+		}
+		listBucket := allLists.Bucket([]byte(listName))
+		if listBucket == nil {
+			return fmt.Errorf("list %s not found", listName)
+		}
+		var err error
+		tasks, err = getArchive(listBucket, since)
+		return err
+	})
+	return tasks, err
+}
+
+// Search the tasks of every list. Lists without matches are left out and the
+// results are sorted by list name.
+func (db *DB) Search(query string, opts SearchOptions) ([]SearchResult, error) {
+	pattern, err := CompileSearch(query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []SearchResult{}
+	err = db.BoltDB.View(func(tx *bolt.Tx) error {
+		allLists := tx.Bucket([]byte("lists"))
+		if allLists == nil {
+			return fmt.Errorf("lists bucket not found - likely issue with database initialization")
+		}
+
+		return allLists.ForEach(func(k, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			list, err := readList(allLists, string(k))
+			if err != nil {
+				return fmt.Errorf("failed to load list %s: %w", k, err)
+			}
+			if matches := list.Search(pattern, opts); len(matches) > 0 {
+				results = append(results, SearchResult{List: list.Info.Name, Matches: matches})
+			}
+			return nil
+		})
+	})
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].List < results[j].List
+	})
+	return results, err
+}
+
+// count how many tasks carry each tag across all lists
+func (db *DB) GetTagCounts() (map[string]int, error) {
+	counts := make(map[string]int)
+	err := db.BoltDB.View(func(tx *bolt.Tx) error {
+		allLists := tx.Bucket([]byte("lists"))
+		if allLists == nil {
+			return fmt.Errorf("lists bucket not found - likely issue with database initialization")
+		}
+
+		return allLists.ForEach(func(k, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			_, dataBucket, err := openList(allLists, string(k), false)
+			if err != nil {
+				return fmt.Errorf("failed to open list %s: %w", k, err)
+			}
+			data, err := getData(dataBucket)
+			if err != nil {
+				return fmt.Errorf("failed to get data for list %s: %w", k, err)
+			}
+			for _, task := range data.Tasks {
+				for _, tag := range task.AllTags() {
+					counts[tag]++
+				}
+			}
+			return nil
+		})
+	})
+	return counts, err
+}
+
 func (db *DB) ListExists(name string) (bool, error) {
 	allInfo, err := db.GetInfo()
 	if err != nil {
@@ -3,6 +3,8 @@ package core
 import (
 	"encoding/binary"
 	"fmt"
+	"strings"
+	"time"
 
 	bolt "go.etcd.io/bbolt"
 )
@@ -50,6 +52,25 @@ func bytesToInts(b []byte) []int {
 	return ints
 }
 
+func stringsToBytes(strs []string) []byte {
+	return []byte(strings.Join(strs, "\n"))
+}
+
+func bytesToStrings(b []byte) []string {
+	if len(b) == 0 {
+		return []string{}
+	}
+	return strings.Split(string(b), "\n")
+}
+
+func timeToBytes(t time.Time) []byte {
+	return itob(int(t.Unix()))
+}
+
+func bytesToTime(b []byte) time.Time {
+	return time.Unix(int64(btoi(b)), 0)
+}
+
 // ------------------------------------- Transaction Helper Functions ---------------------------------
 
 // Save fields from the given Task struct into the bucket
@@ -59,11 +80,71 @@ func saveTask(bucket *bolt.Bucket, task Task) error {
 		return err
 	}
 	taskBucket.Put([]byte("id"), itob(task.Id))
+	if task.ParentId == 0 {
+		taskBucket.Delete([]byte("parent"))
+	} else {
+		taskBucket.Put([]byte("parent"), itob(task.ParentId))
+	}
 	taskBucket.Put([]byte("description"), []byte(task.Description))
 	taskBucket.Put([]byte("done"), boolToBytes(task.Done))
+	taskBucket.Put([]byte("priority"), itob(int(task.Priority)))
+	if len(task.Tags) == 0 {
+		taskBucket.Delete([]byte("tags"))
+	} else {
+		taskBucket.Put([]byte("tags"), stringsToBytes(task.Tags))
+	}
+	if task.Recurrence.IsSet() {
+		taskBucket.Put([]byte("recur"), []byte(task.Recurrence.String()))
+	} else {
+		taskBucket.Delete([]byte("recur"))
+	}
+	if task.NextId == 0 {
+		taskBucket.Delete([]byte("next"))
+	} else {
+		taskBucket.Put([]byte("next"), itob(task.NextId))
+	}
+	if task.Notes == "" {
+		taskBucket.Delete([]byte("notes"))
+	} else {
+		taskBucket.Put([]byte("notes"), []byte(task.Notes))
+	}
+	if task.UUID == "" {
+		taskBucket.Delete([]byte("uuid"))
+	} else {
+		taskBucket.Put([]byte("uuid"), []byte(task.UUID))
+	}
+	times := map[string]time.Time{
+		"due":       task.Due,
+		"scheduled": task.Scheduled,
+		"created":   task.CreatedAt,
+		"updated":   task.UpdatedAt,
+		"completed": task.CompletedAt,
+	}
+	for key, t := range times {
+		if err := putTime(taskBucket, key, t); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// Store an optional time under the given key, deleting the key for the zero time.
+func putTime(bucket *bolt.Bucket, key string, t time.Time) error {
+	if t.IsZero() {
+		return bucket.Delete([]byte(key))
+	}
+	return bucket.Put([]byte(key), timeToBytes(t))
+}
+
+// Read an optional time stored with putTime. Missing keys give the zero time.
+func getTime(bucket *bolt.Bucket, key string) time.Time {
+	b := bucket.Get([]byte(key))
+	if len(b) != 8 {
+		return time.Time{}
+	}
+	return bytesToTime(b)
+}
+
 // Populate fields of Task struct by reading from the given bucket.
 func getTask(bucket *bolt.Bucket) (Task, error) {
 	task := &Task{}
@@ -82,8 +163,28 @@ func getTask(bucket *bolt.Bucket) (Task, error) {
 	}
 
 	task.Id = btoi(id)
+	if parent := bucket.Get([]byte("parent")); len(parent) == 8 {
+		task.ParentId = btoi(parent)
+	}
 	task.Description = string(description)
 	task.Done = bytesToBool(done)
+	if priority := bucket.Get([]byte("priority")); len(priority) == 8 {
+		task.Priority = Priority(btoi(priority))
+	}
+	task.Tags = bytesToStrings(bucket.Get([]byte("tags")))
+	task.Due = getTime(bucket, "due")
+	task.Scheduled = getTime(bucket, "scheduled")
+	task.Notes = string(bucket.Get([]byte("notes")))
+	task.UUID = string(bucket.Get([]byte("uuid")))
+	if recurrence, err := ParseRecurrence(string(bucket.Get([]byte("recur")))); err == nil {
+		task.Recurrence = recurrence
+	}
+	if next := bucket.Get([]byte("next")); len(next) == 8 {
+		task.NextId = btoi(next)
+	}
+	task.CreatedAt = getTime(bucket, "created")
+	task.UpdatedAt = getTime(bucket, "updated")
+	task.CompletedAt = getTime(bucket, "completed")
 
 	return *task, nil
 }
@@ -105,7 +206,19 @@ func saveInfo(bucket *bolt.Bucket, info ListInfo) error {
 	if err != nil {
 		return err
 	}
-	return bucket.Put([]byte("numTasks"), itob(info.NumTasks))
+	err = bucket.Put([]byte("numTasks"), itob(info.NumTasks))
+	if err != nil {
+		return err
+	}
+	err = bucket.Put([]byte("nextId"), itob(info.NextId))
+	if err != nil {
+		return err
+	}
+	err = putTime(bucket, "created", info.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return putTime(bucket, "updated", info.UpdatedAt)
 }
 
 // Populate and return a ListInfo struct by reading fields from the given bucket.
@@ -138,6 +251,11 @@ func getInfo(bucket *bolt.Bucket) (ListInfo, error) {
 	info.NumDone = btoi(numDone)
 	info.NumPending = btoi(numPending)
 	info.NumTasks = btoi(numTasks)
+	info.CreatedAt = getTime(bucket, "created")
+	info.UpdatedAt = getTime(bucket, "updated")
+	if nextId := bucket.Get([]byte("nextId")); len(nextId) == 8 {
+		info.NextId = btoi(nextId)
+	}
 	return info, nil
 }
 
@@ -161,6 +279,23 @@ func saveData(bucket *bolt.Bucket, list List) error {
 		}
 	}
 
+	// delete tasks that were removed from the list
+	removed := [][]byte{}
+	err = taskBucket.ForEach(func(k, v []byte) error {
+		if _, ok := list.Tasks[btoi(k)]; v == nil && !ok {
+			removed = append(removed, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range removed {
+		if err := taskBucket.DeleteBucket(k); err != nil {
+			return err
+		}
+	}
+
 	// skip saving usedIds because it is cheaper to reconstruct it when loading
 	return nil
 }
@@ -201,6 +336,69 @@ func getData(bucket *bolt.Bucket) (List, error) {
 	return list, nil
 }
 
+// Give the tasks stored before tasks had UUIDs one, so every export of a task
+// uses the same UUID. Lists whose data cannot be found are left alone.
+func assignMissingUUIDs(allLists *bolt.Bucket) error {
+	var names [][]byte
+	err := allLists.ForEach(func(k, v []byte) error {
+		if v == nil {
+			names = append(names, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		var taskBucket *bolt.Bucket
+		if dataBucket := allLists.Bucket(name).Bucket([]byte("data")); dataBucket != nil {
+			taskBucket = dataBucket.Bucket([]byte("tasks"))
+		}
+		if taskBucket == nil {
+			continue
+		}
+		var missing [][]byte
+		err := taskBucket.ForEach(func(k, v []byte) error {
+			if v == nil && taskBucket.Bucket(k).Get([]byte("uuid")) == nil {
+				missing = append(missing, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range missing {
+			if err := taskBucket.Bucket(k).Put([]byte("uuid"), []byte(NewUUID())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Read a whole list from its info and data buckets, recomputing the task counts.
+// Lists saved before task ids were short have their tasks renumbered, and the
+// new ids are stored the next time the list is saved.
+func loadList(infoBucket, dataBucket *bolt.Bucket) (List, error) {
+	info, err := getInfo(infoBucket)
+	if err != nil {
+		return List{}, fmt.Errorf("failed to get info: %w", err)
+	}
+	list, err := getData(dataBucket)
+	if err != nil {
+		return List{}, fmt.Errorf("failed to get data: %w", err)
+	}
+	list.Info = info
+	if info.NextId == 0 {
+		list.renumberTasks()
+	}
+
+	// align list info with data
+	list.countTasks()
+	return list, nil
+}
+
 // Retrieve the info and data sub-buckets associated with the bucket with
 // the given list name, and return an error for missing buckets depending
 // on the existOkay flag.
@@ -237,6 +435,41 @@ func openList(b *bolt.Bucket, listName string, notExistOk bool) (infoBucket, dat
 	return infoBucket, dataBucket, nil
 }
 
+// Read the list stored under the given name in b, or nil if there is none.
+func readList(b *bolt.Bucket, listName string) (*List, error) {
+	if b.Bucket([]byte(listName)) == nil {
+		return nil, nil
+	}
+	infoBucket, dataBucket, err := openList(b, listName, false)
+	if err != nil {
+		return nil, err
+	}
+	list, err := loadList(infoBucket, dataBucket)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Store the list under the given name in b, creating the buckets it needs.
+func writeList(b *bolt.Bucket, listName string, list List) error {
+	infoBucket, dataBucket, err := openList(b, listName, true)
+	if err != nil {
+		return err
+	}
+
+	// align list info with data
+	list.countTasks()
+
+	// save info into meta data bucket
+	if err := saveInfo(infoBucket, list.Info); err != nil {
+		return err
+	}
+
+	// save data into data bucket
+	return saveData(dataBucket, list)
+}
+
 func copyBucket(src, dst *bolt.Bucket) error {
 	return src.ForEach(func(k, v []byte) error {
 		if v == nil {
@@ -277,60 +510,42 @@ func setCurrListName(tx *bolt.Tx, name string) error {
 	return currentList.Put([]byte("name"), []byte(name))
 }
 
-// delete all tasks that are marked as done
-func cleanList(b *bolt.Bucket) (int, error) {
-	dataBucket := b.Bucket([]byte("data"))
-	if dataBucket == nil {
-		return 0, fmt.Errorf("data bucket not found")
+// Delete all tasks that are marked as done, moving them to the archive of the list
+// or to the trash, and record the change in the journal. See List.RemoveCompleted.
+func cleanList(tx *bolt.Tx, allLists *bolt.Bucket, name string, archive bool) (int, error) {
+	list, err := readList(allLists, name)
+	if err != nil {
+		return 0, err
 	}
-
-	// remove tasks that are done
-	taskListBucket := dataBucket.Bucket([]byte("tasks"))
-	if taskListBucket == nil {
-		return 0, fmt.Errorf("tasks bucket not found")
+	if list == nil {
+		return 0, nil // skip if the list does not exist
 	}
-	var numRemoved int
-	remainingIds := []int{}
-	err := taskListBucket.ForEach(func(k, v []byte) error {
-		if v == nil {
-			taskBucket := taskListBucket.Bucket(k)
-			if taskBucket == nil {
-				return fmt.Errorf("task bucket %s not found", string(k))
-			}
 
-			task, err := getTask(taskBucket)
-			if err != nil {
-				return nil // skip if task bucket is empty or doesn't exist
-			}
-
-			if task.Done {
-				numRemoved++
-				return taskListBucket.DeleteBucket(k)
-			} else {
-				remainingIds = append(remainingIds, btoi(k))
-			}
-		}
-		return nil
-	})
-	if err != nil {
+	before := list.Clone()
+	numRemoved := list.RemoveCompleted()
+	if numRemoved == 0 {
+		return 0, nil
+	}
+	if err := writeList(allLists, name, *list); err != nil {
 		return 0, err
 	}
 
-	// update TaskIds
-	dataBucket.Put([]byte("taskIds"), intsToBytes(remainingIds))
-
-	// update info with number of total and done tasks
-	infoBucket := b.Bucket([]byte("info"))
-	if infoBucket == nil {
-		return 0, fmt.Errorf("info bucket not found")
+	removed := NewList(name)
+	removed.Info.NextId = before.Info.NextId
+	for _, id := range before.TaskIds {
+		if _, ok := list.Tasks[id]; !ok {
+			removed.TaskIds = append(removed.TaskIds, id)
+			removed.Tasks[id] = before.Tasks[id]
+		}
 	}
-
-	info, err := getInfo(infoBucket)
-	if err != nil {
+	if archive {
+		if err := addToArchive(allLists.Bucket([]byte(name)), removed.DisplayOrder()); err != nil {
+			return 0, err
+		}
+		return numRemoved, addJournalEntry(tx, OpArchive, &before, list)
+	}
+	if _, err := addToTrash(tx, TrashedTasks, removed); err != nil {
 		return 0, err
 	}
-
-	info.NumTasks -= numRemoved
-	info.NumDone = 0
-	return numRemoved, saveInfo(infoBucket, info)
+	return numRemoved, addJournalEntry(tx, OpClean, &before, list)
 }
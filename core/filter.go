@@ -0,0 +1,431 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// A parsed filter such as `pending and tag:backend and due<7d`. Conditions are
+// combined with `and` (which may be left out), `or`, `not` and parentheses.
+//
+// Conditions:
+//
+//	done, pending, overdue, recurring   completion and date state of a task
+//	word or "quoted words"              the description contains the text (ignoring case)
+//	desc:text, notes:text               the description or notes contain the text (= for an exact match)
+//	tag:name                            the task carries the tag, see Task.HasTag
+//	priority>=high                      compare the priority (none, low, medium, high, urgent)
+//	due<7d, created>=-2w, due:today     compare a date, see below
+//	id=3                                compare the task id
+//	has:due                             the field is set (due, scheduled, notes, tags, recur, priority, parent)
+//
+// Dates are compared by day and may be given as YYYY-MM-DD, today, tomorrow,
+// yesterday, or a number of days or weeks from today such as 7d or -2w. Tasks
+// without the date never match a comparison. The operators are :, =, !=, <, <=, > and >=.
+type Filter struct {
+	query string
+	match func(task *Task) bool
+}
+
+// the filter as it was written
+func (f *Filter) String() string {
+	return f.query
+}
+
+// whether the task satisfies the filter
+func (f *Filter) Matches(task *Task) bool {
+	return f.match(task)
+}
+
+// Parse a filter query. Relative dates such as 7d are resolved against now.
+func ParseFilter(query string, now time.Time) (*Filter, error) {
+	p := filterParser{query: query, now: now}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("invalid filter: the filter is empty")
+	}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != nil {
+		return nil, p.errorf(tok.pos, "unexpected %q", tok.text)
+	}
+	return &Filter{query: query, match: match}, nil
+}
+
+// Return the tasks that match the filter, keeping their order.
+func FilterTasks(tasks []*Task, f *Filter) []*Task {
+	out := []*Task{}
+	for _, task := range tasks {
+		if f.Matches(task) {
+			out = append(out, task)
+		}
+	}
+	return out
+}
+
+// Copy of the list with only the tasks that match the filter. Subtasks of a
+// task that does not match are moved up to its closest matching ancestor.
+func (l List) Filtered(f *Filter) List {
+	filtered := l.Clone()
+	filtered.TaskIds = []int{}
+	filtered.Tasks = map[int]*Task{}
+	for _, id := range l.TaskIds {
+		if task := l.Tasks[id]; f.Matches(task) {
+			filtered.TaskIds = append(filtered.TaskIds, id)
+			copied := *task
+			filtered.Tasks[id] = &copied
+		}
+	}
+	for _, task := range filtered.Tasks {
+		parentId := task.ParentId
+		for parentId != 0 {
+			if _, ok := filtered.Tasks[parentId]; ok {
+				break
+			}
+			parent, ok := l.Tasks[parentId]
+			if !ok {
+				parentId = 0
+				break
+			}
+			parentId = parent.ParentId
+		}
+		task.ParentId = parentId
+	}
+	filtered.countTasks()
+	return filtered
+}
+
+// ------------------------------- parsing ---------------------------------
+
+type filterToken struct {
+	text string // with quotes kept, so that quoted words are never keywords
+	pos  int
+}
+
+type filterParser struct {
+	query  string
+	now    time.Time
+	tokens []filterToken
+	next   int
+}
+
+// error pointing at the given byte offset of the query
+func (p *filterParser) errorf(pos int, format string, args ...any) error {
+	return fmt.Errorf("invalid filter at column %d: %s\n\t%s\n\t%s^", pos+1, fmt.Sprintf(format, args...), p.query, strings.Repeat(" ", pos))
+}
+
+// split the query into words, parentheses and quoted text
+func (p *filterParser) tokenize() error {
+	runes := []rune(p.query)
+	offset := func(i int) int { return len(string(runes[:i])) }
+	for i := 0; i < len(runes); {
+		switch {
+		case unicode.IsSpace(runes[i]):
+			i++
+		case runes[i] == '(' || runes[i] == ')':
+			p.tokens = append(p.tokens, filterToken{text: string(runes[i]), pos: offset(i)})
+			i++
+		default:
+			start := i
+			quoted := false
+			for i < len(runes) && (quoted || !(unicode.IsSpace(runes[i]) || runes[i] == '(' || runes[i] == ')')) {
+				if runes[i] == '"' {
+					quoted = !quoted
+				}
+				i++
+			}
+			if quoted {
+				return p.errorf(offset(start), "missing closing quote")
+			}
+			p.tokens = append(p.tokens, filterToken{text: string(runes[start:i]), pos: offset(start)})
+		}
+	}
+	return nil
+}
+
+func (p *filterParser) peek() *filterToken {
+	if p.next >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.next]
+}
+
+// whether the next token is the given keyword
+func (p *filterParser) peekKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok != nil && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *filterParser) parseOr() (func(*Task) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.next++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(t *Task) bool { return l(t) || right(t) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (func(*Task) bool, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok == nil || tok.text == ")" || p.peekKeyword("or") {
+			return left, nil
+		}
+		if p.peekKeyword("and") {
+			p.next++
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(t *Task) bool { return l(t) && right(t) }
+	}
+}
+
+func (p *filterParser) parseUnary() (func(*Task) bool, error) {
+	tok := p.peek()
+	if tok == nil {
+		return nil, p.errorf(len(p.query), "expected a condition")
+	}
+	switch {
+	case p.peekKeyword("not"):
+		p.next++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(t *Task) bool { return !operand(t) }, nil
+	case p.peekKeyword("and") || p.peekKeyword("or"):
+		return nil, p.errorf(tok.pos, "expected a condition before %q", tok.text)
+	case tok.text == ")":
+		return nil, p.errorf(tok.pos, "unexpected \")\"")
+	case tok.text == "(":
+		p.next++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.text != ")" {
+			return nil, p.errorf(tok.pos, "missing \")\" for this \"(\"")
+		}
+		p.next++
+		return inner, nil
+	}
+	p.next++
+	return p.parseCondition(*tok)
+}
+
+var filterOperator = regexp.MustCompile(`^([A-Za-z]+)(<=|>=|!=|:|=|<|>)(.*)$`)
+
+func (p *filterParser) parseCondition(tok filterToken) (func(*Task) bool, error) {
+	parts := filterOperator.FindStringSubmatch(tok.text)
+	if parts == nil {
+		return p.parseKeyword(tok)
+	}
+	field, op, value := strings.ToLower(parts[1]), parts[2], strings.Trim(parts[3], `"`)
+	valuePos := tok.pos + len(parts[1]) + len(op)
+	if value == "" {
+		return nil, p.errorf(valuePos, "missing value after %s%s", parts[1], op)
+	}
+	unsupported := func() error {
+		return p.errorf(tok.pos+len(parts[1]), "operator %s cannot be used with %s", op, field)
+	}
+
+	switch field {
+	case "desc", "description", "notes":
+		get := func(t *Task) string { return t.Description }
+		if field == "notes" {
+			get = func(t *Task) string { return t.Notes }
+		}
+		needle := strings.ToLower(value)
+		switch op {
+		case ":":
+			return func(t *Task) bool { return strings.Contains(strings.ToLower(get(t)), needle) }, nil
+		case "=":
+			return func(t *Task) bool { return strings.EqualFold(get(t), value) }, nil
+		case "!=":
+			return func(t *Task) bool { return !strings.Contains(strings.ToLower(get(t)), needle) }, nil
+		}
+		return nil, unsupported()
+
+	case "tag":
+		switch op {
+		case ":", "=":
+			return func(t *Task) bool { return t.HasTag(value) }, nil
+		case "!=":
+			return func(t *Task) bool { return !t.HasTag(value) }, nil
+		}
+		return nil, unsupported()
+
+	case "priority", "p":
+		priority, err := ParsePriority(value)
+		if err != nil {
+			return nil, p.errorf(valuePos, "%v", err)
+		}
+		return func(t *Task) bool { return compareInts(int(t.Priority), op, int(priority)) }, nil
+
+	case "id":
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, p.errorf(valuePos, "invalid id %q - expected a number", value)
+		}
+		return func(t *Task) bool { return compareInts(t.Id, op, id) }, nil
+
+	case "due", "scheduled", "created", "updated", "completed":
+		get := dateFields[field]
+		if strings.EqualFold(value, "none") {
+			switch op {
+			case ":", "=":
+				return func(t *Task) bool { return get(t).IsZero() }, nil
+			case "!=":
+				return func(t *Task) bool { return !get(t).IsZero() }, nil
+			}
+			return nil, unsupported()
+		}
+		day, err := resolveFilterDay(value, p.now)
+		if err != nil {
+			return nil, p.errorf(valuePos, "%v", err)
+		}
+		return func(t *Task) bool { return compareDays(get(t), op, day) }, nil
+
+	case "has":
+		if op != ":" && op != "=" {
+			return nil, unsupported()
+		}
+		has, ok := hasFields[strings.ToLower(value)]
+		if !ok {
+			return nil, p.errorf(valuePos, "unknown field %q - expected one of due, scheduled, notes, tags, recur, priority or parent", value)
+		}
+		return has, nil
+	}
+	return nil, p.errorf(tok.pos, "unknown field %q - expected one of desc, notes, tag, priority, id, due, scheduled, created, updated, completed or has", parts[1])
+}
+
+// a condition without an operator: a keyword or text in the description
+func (p *filterParser) parseKeyword(tok filterToken) (func(*Task) bool, error) {
+	if !strings.HasPrefix(tok.text, `"`) {
+		switch strings.ToLower(tok.text) {
+		case "done":
+			return func(t *Task) bool { return t.Done }, nil
+		case "pending":
+			return func(t *Task) bool { return !t.Done }, nil
+		case "overdue":
+			now := p.now
+			return func(t *Task) bool { return t.IsOverdue(now) }, nil
+		case "recurring":
+			return func(t *Task) bool { return t.Recurrence.IsSet() }, nil
+		}
+	}
+	if strings.ContainsAny(tok.text, "<>=!") && !strings.Contains(tok.text, `"`) {
+		return nil, p.errorf(tok.pos, "invalid condition %q - expected field:value, e.g. tag:backend or due<7d", tok.text)
+	}
+	needle := strings.ToLower(strings.ReplaceAll(tok.text, `"`, ""))
+	return func(t *Task) bool { return strings.Contains(strings.ToLower(t.Description), needle) }, nil
+}
+
+var dateFields = map[string]func(*Task) time.Time{
+	"due":       func(t *Task) time.Time { return t.Due },
+	"scheduled": func(t *Task) time.Time { return t.Scheduled },
+	"created":   func(t *Task) time.Time { return t.CreatedAt },
+	"updated":   func(t *Task) time.Time { return t.UpdatedAt },
+	"completed": func(t *Task) time.Time { return t.CompletedAt },
+}
+
+var hasFields = map[string]func(*Task) bool{
+	"due":       func(t *Task) bool { return !t.Due.IsZero() },
+	"scheduled": func(t *Task) bool { return !t.Scheduled.IsZero() },
+	"notes":     func(t *Task) bool { return t.Notes != "" },
+	"tags":      func(t *Task) bool { return len(t.AllTags()) > 0 },
+	"recur":     func(t *Task) bool { return t.Recurrence.IsSet() },
+	"priority":  func(t *Task) bool { return t.Priority != PriorityNone },
+	"parent":    func(t *Task) bool { return t.ParentId != 0 },
+}
+
+var relativeDay = regexp.MustCompile(`^([+-]?\d+)([dw])$`)
+
+// the start of the day named by a filter value such as today, -3d or 2025-06-20
+func resolveFilterDay(value string, now time.Time) (time.Time, error) {
+	today := StartOfDay(now)
+	switch strings.ToLower(value) {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if parts := relativeDay.FindStringSubmatch(strings.ToLower(value)); parts != nil {
+		n, _ := strconv.Atoi(parts[1])
+		if parts[2] == "w" {
+			n *= 7
+		}
+		return today.AddDate(0, 0, n), nil
+	}
+	t, err := ParseDate(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q - expected YYYY-MM-DD, today, tomorrow, yesterday or a number of days or weeks such as 7d or -2w", value)
+	}
+	return StartOfDay(t), nil
+}
+
+func compareInts(a int, op string, b int) bool {
+	switch op {
+	case ":", "=":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+// compare a time to a whole day. The zero time never matches.
+func compareDays(t time.Time, op string, day time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	next := day.AddDate(0, 0, 1)
+	switch op {
+	case ":", "=":
+		return !t.Before(day) && t.Before(next)
+	case "!=":
+		return t.Before(day) || !t.Before(next)
+	case "<":
+		return t.Before(day)
+	case "<=":
+		return t.Before(next)
+	case ">":
+		return !t.Before(next)
+	case ">=":
+		return !t.Before(day)
+	}
+	return false
+}
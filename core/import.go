@@ -0,0 +1,217 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// what to do when an imported list has the name of an existing list
+type ConflictStrategy string
+
+const (
+	ConflictFail    ConflictStrategy = "fail"    // abort the whole import
+	ConflictSkip    ConflictStrategy = "skip"    // keep the existing list and leave out the imported one
+	ConflictReplace ConflictStrategy = "replace" // move the existing list to the trash
+	ConflictMerge   ConflictStrategy = "merge"   // add the imported tasks the existing list does not have
+	ConflictRename  ConflictStrategy = "rename"  // import the list under a new name
+)
+
+var conflictStrategies = []ConflictStrategy{ConflictFail, ConflictSkip, ConflictReplace, ConflictMerge, ConflictRename}
+
+func ParseConflictStrategy(s string) (ConflictStrategy, error) {
+	for _, strategy := range conflictStrategies {
+		if ConflictStrategy(strings.ToLower(strings.TrimSpace(s))) == strategy {
+			return strategy, nil
+		}
+	}
+	names := make([]string, len(conflictStrategies))
+	for i, strategy := range conflictStrategies {
+		names[i] = string(strategy)
+	}
+	return "", fmt.Errorf("invalid conflict strategy %q - expected one of %s", s, strings.Join(names, ", "))
+}
+
+type ImportOptions struct {
+	OnConflict ConflictStrategy
+	MergeById  bool   // when merging, treat tasks with the same id as the same task instead of tasks with the same description
+	Current    string // imported list to make current if no current list is set or it no longer exists
+}
+
+// what happened to one imported list
+type ImportResult struct {
+	Source  string // name of the list in the imported file
+	List    string // name of the list in the database, which differs from Source if it was renamed
+	Action  string // one of created, skipped, replaced, merged or renamed
+	Added   int    // number of tasks added to the database
+	Skipped int    // number of tasks that were left out because the list already had them
+}
+
+// one line description of the result, e.g. "work: merged, 3 tasks added, 2 already present"
+func (r ImportResult) Summary() string {
+	summary := fmt.Sprintf("%s: %s", r.Source, r.Action)
+	if r.List != r.Source {
+		summary += fmt.Sprintf(" to %q", r.List)
+	}
+	if r.Action == "skipped" {
+		return summary
+	}
+	summary += fmt.Sprintf(", %d tasks added", r.Added)
+	if r.Skipped > 0 {
+		summary += fmt.Sprintf(", %d already present", r.Skipped)
+	}
+	return summary
+}
+
+// Add the tasks of other that the list does not have yet, keeping their order
+// and nesting. A task is already present if the list has a task with the same
+// UUID, or else with the same parent and description, or with the same id if
+// byId is set. Returns the number of added and skipped tasks.
+func (l *List) Merge(other List, byId bool) (int, int, error) {
+	added, skipped := 0, 0
+	newIds := map[int]int{} // ids of other's tasks in the list
+	var addedIds []int      // ids of the added tasks in the list
+	for _, id := range other.TaskIds {
+		task := *other.Tasks[id]
+		parentId := newIds[task.ParentId]
+
+		if existing := l.findMergeTarget(task, parentId, byId); existing != nil {
+			newIds[id] = existing.Id
+			skipped++
+			continue
+		}
+
+		if _, used := l.UsedIds[id]; byId && !used {
+			l.reserveTaskId(id)
+		} else {
+			newId, err := l.generateTaskId()
+			if err != nil {
+				return added, skipped, err
+			}
+			task.Id = newId
+		}
+		newIds[id] = task.Id
+		task.ParentId = parentId
+		task.Tags = append([]string{}, task.Tags...)
+		task.Recurrence.Weekdays = append([]time.Weekday{}, task.Recurrence.Weekdays...)
+		if err := l.AddTask(task); err != nil {
+			return added, skipped, err
+		}
+		addedIds = append(addedIds, task.Id)
+		added++
+	}
+
+	// links between recurring occurrences follow the new ids, links to tasks not in other are dropped
+	for _, id := range addedIds {
+		task := l.Tasks[id]
+		task.NextId = newIds[task.NextId]
+	}
+	return added, skipped, nil
+}
+
+// the task of the list that an imported task is merged into, or nil if it is new
+func (l *List) findMergeTarget(task Task, parentId int, byId bool) *Task {
+	if task.UUID != "" {
+		for _, id := range l.TaskIds {
+			if existing := l.Tasks[id]; existing.UUID == task.UUID {
+				return existing
+			}
+		}
+	}
+	if byId {
+		return l.Tasks[task.Id]
+	}
+	for _, id := range l.TaskIds {
+		existing := l.Tasks[id]
+		if existing.ParentId == parentId && existing.Description == task.Description {
+			return existing
+		}
+	}
+	return nil
+}
+
+// Import the lists in a single transaction, so that either all lists are
+// imported or, if anything fails, none of them. Returns what happened to each
+// list in the order they were given.
+func (db *DB) ImportLists(lists []List, opts ImportOptions) ([]ImportResult, error) {
+	var results []ImportResult
+	err := db.BoltDB.Update(func(tx *bolt.Tx) error {
+		results = nil
+		allLists := tx.Bucket([]byte("lists"))
+		if allLists == nil {
+			return fmt.Errorf("lists bucket not found - likely issue with database initialization")
+		}
+
+		for _, list := range lists {
+			result, err := importList(tx, allLists, list, opts)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+
+		for _, result := range results {
+			if result.Source != opts.Current {
+				continue
+			}
+			current := getCurrListName(tx)
+			if current == "" || allLists.Bucket([]byte(current)) == nil {
+				return setCurrListName(tx, result.List)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func importList(tx *bolt.Tx, allLists *bolt.Bucket, list List, opts ImportOptions) (ImportResult, error) {
+	name := list.Info.Name
+	result := ImportResult{Source: name, List: name, Action: "created", Added: len(list.Tasks)}
+
+	existing, err := readList(allLists, name)
+	if err != nil {
+		return result, fmt.Errorf("list %q: %v", name, err)
+	}
+	if existing != nil {
+		switch opts.OnConflict {
+		case ConflictSkip:
+			result.Action = "skipped"
+			result.Added = 0
+			return result, nil
+		case ConflictReplace:
+			if err := deleteList(tx, allLists, name); err != nil {
+				return result, err
+			}
+			result.Action = "replaced"
+		case ConflictMerge:
+			merged := existing.Clone()
+			result.Action = "merged"
+			result.Added, result.Skipped, err = merged.Merge(list, opts.MergeById)
+			if err != nil {
+				return result, fmt.Errorf("list %q: %v", name, err)
+			}
+			if err := writeList(allLists, name, merged); err != nil {
+				return result, err
+			}
+			return result, addJournalEntry(tx, OpImport, existing, &merged)
+		case ConflictRename:
+			for i := 2; allLists.Bucket([]byte(list.Info.Name)) != nil; i++ {
+				list.Info.Name = fmt.Sprintf("%s-%d", name, i)
+			}
+			result.List = list.Info.Name
+			result.Action = "renamed"
+		default:
+			return result, fmt.Errorf("list %q already exists", name)
+		}
+	}
+
+	if err := writeList(allLists, list.Info.Name, list); err != nil {
+		return result, err
+	}
+	return result, addJournalEntry(tx, OpImport, nil, &list)
+}
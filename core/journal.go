@@ -0,0 +1,346 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// kinds of changes recorded in the journal
+const (
+	OpSave    = "save"
+	OpClean   = "clean"
+	OpArchive = "archive"
+	OpRename  = "rename"
+	OpDelete  = "delete"
+	OpRestore = "restore"
+	OpImport  = "import"
+)
+
+// Limits of the journal, older entries are dropped first. Every entry holds
+// the whole list as it was before the change and the tasks the change touched,
+// so the size limit is what keeps the journal of a long list from growing
+// with every edit.
+const maxJournalEntries = 500
+const maxJournalBytes = 8 << 20
+
+// A change to a list recorded in the journal. Before is nil if the change
+// created the list and After is nil if the change deleted it.
+type JournalEntry struct {
+	Id        int
+	Time      time.Time
+	List      string // name of the list after the change, or before it for deletions
+	Operation string
+	Before    *List
+	After     *List
+}
+
+// the tasks that differ between two states of a list
+type ListDiff struct {
+	Added     []*Task
+	Removed   []*Task
+	Changed   []*Task // as they are after the change
+	Reordered bool
+}
+
+// Compare two states of a list by task id. A nil list has no tasks.
+func DiffLists(before, after *List) ListDiff {
+	var diff ListDiff
+	beforeTasks, afterTasks := map[int]*Task{}, map[int]*Task{}
+	var beforeIds, afterIds []int
+	if before != nil {
+		beforeTasks, beforeIds = before.Tasks, before.TaskIds
+	}
+	if after != nil {
+		afterTasks, afterIds = after.Tasks, after.TaskIds
+	}
+
+	for _, id := range afterIds {
+		task := afterTasks[id]
+		old, ok := beforeTasks[id]
+		if !ok {
+			diff.Added = append(diff.Added, task)
+		} else if !sameTask(old, task) {
+			diff.Changed = append(diff.Changed, task)
+		}
+	}
+	for _, id := range beforeIds {
+		if _, ok := afterTasks[id]; !ok {
+			diff.Removed = append(diff.Removed, beforeTasks[id])
+		}
+	}
+
+	// compare the order of the tasks present in both states
+	var kept []int
+	for _, id := range beforeIds {
+		if _, ok := afterTasks[id]; ok {
+			kept = append(kept, id)
+		}
+	}
+	i := 0
+	for _, id := range afterIds {
+		if _, ok := beforeTasks[id]; !ok {
+			continue
+		}
+		if kept[i] != id {
+			diff.Reordered = true
+			break
+		}
+		i++
+	}
+	return diff
+}
+
+// whether the parts of two tasks a user can see or edit are the same
+func sameTask(a, b *Task) bool {
+	return a.ParentId == b.ParentId &&
+		a.Description == b.Description &&
+		a.Done == b.Done &&
+		a.Priority == b.Priority &&
+		strings.Join(a.Tags, "\n") == strings.Join(b.Tags, "\n") &&
+		a.Due.Unix() == b.Due.Unix() &&
+		a.Scheduled.Unix() == b.Scheduled.Unix() &&
+		a.Notes == b.Notes &&
+		a.Recurrence.String() == b.Recurrence.String()
+}
+
+func (d ListDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && !d.Reordered
+}
+
+// e.g. "2 added, 1 changed, reordered"
+func (d ListDiff) String() string {
+	parts := []string{}
+	if len(d.Added) > 0 {
+		parts = append(parts, fmt.Sprintf("%d added", len(d.Added)))
+	}
+	if len(d.Changed) > 0 {
+		parts = append(parts, fmt.Sprintf("%d changed", len(d.Changed)))
+	}
+	if len(d.Removed) > 0 {
+		parts = append(parts, fmt.Sprintf("%d removed", len(d.Removed)))
+	}
+	if d.Reordered {
+		parts = append(parts, "reordered")
+	}
+	if len(parts) == 0 {
+		return "no task changes"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (e JournalEntry) Diff() ListDiff {
+	return DiffLists(e.Before, e.After)
+}
+
+// one line description of the change, e.g. "renamed from \"old\", 1 added"
+func (e JournalEntry) Summary() string {
+	switch {
+	case e.Before == nil:
+		return fmt.Sprintf("created with %d tasks", len(e.After.Tasks))
+	case e.After == nil:
+		return fmt.Sprintf("deleted with %d tasks", len(e.Before.Tasks))
+	case e.Before.Info.Name != e.After.Info.Name:
+		summary := fmt.Sprintf("renamed from %q", e.Before.Info.Name)
+		if diff := e.Diff(); !diff.IsEmpty() {
+			summary += ", " + diff.String()
+		}
+		return summary
+	}
+	return e.Diff().String()
+}
+
+// whether the entry changed the list with the given name, under its old or new name
+func (e JournalEntry) Touches(listName string) bool {
+	return (e.Before != nil && e.Before.Info.Name == listName) ||
+		(e.After != nil && e.After.Info.Name == listName)
+}
+
+// -------------------------------- storage ---------------------------------
+
+// Append a change of a list to the journal. Saves that do not change any task
+// are not recorded.
+func addJournalEntry(tx *bolt.Tx, op string, before, after *List) error {
+	journal := tx.Bucket([]byte("journal"))
+	if journal == nil {
+		return fmt.Errorf("journal bucket not found - likely issue with database initialization")
+	}
+	if before == nil && after == nil {
+		return nil
+	}
+	if before != nil && after != nil && before.Info.Name == after.Info.Name && DiffLists(before, after).IsEmpty() {
+		return nil
+	}
+
+	seq, err := journal.NextSequence()
+	if err != nil {
+		return err
+	}
+	entry, err := journal.CreateBucket(itob(int(seq)))
+	if err != nil {
+		return err
+	}
+	listName := ""
+	if after != nil {
+		listName = after.Info.Name
+	} else {
+		listName = before.Info.Name
+	}
+	if err := putTime(entry, "time", time.Now()); err != nil {
+		return err
+	}
+	if err := entry.Put([]byte("list"), []byte(listName)); err != nil {
+		return err
+	}
+	if err := entry.Put([]byte("op"), []byte(op)); err != nil {
+		return err
+	}
+	if before != nil {
+		if err := writeList(entry, "before", *before); err != nil {
+			return err
+		}
+	}
+	if after != nil && before != nil {
+		if err := writeListChanges(entry, "after", *before, *after); err != nil {
+			return err
+		}
+	} else if after != nil {
+		if err := writeList(entry, "after", *after); err != nil {
+			return err
+		}
+	}
+	if err := entry.Put([]byte("size"), itob(bucketSize(entry))); err != nil {
+		return err
+	}
+	return trimJournal(journal, int(seq))
+}
+
+// Drop the oldest entries until the journal is within its limits, always
+// keeping the newest entry.
+func trimJournal(journal *bolt.Bucket, newest int) error {
+	total := 0
+	c := journal.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if v == nil {
+			total += entrySize(journal.Bucket(k))
+		}
+	}
+	for k, _ := c.First(); k != nil && btoi(k) < newest; k, _ = c.First() {
+		if btoi(k) > newest-maxJournalEntries && total <= maxJournalBytes {
+			break
+		}
+		total -= entrySize(journal.Bucket(k))
+		if err := journal.DeleteBucket(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// the size of a journal entry, measured for entries written before sizes were stored
+func entrySize(entry *bolt.Bucket) int {
+	if size := entry.Get([]byte("size")); size != nil {
+		return btoi(size)
+	}
+	return bucketSize(entry)
+}
+
+// number of bytes in the keys and values of the bucket and its sub-buckets
+func bucketSize(b *bolt.Bucket) int {
+	size := 0
+	b.ForEach(func(k, v []byte) error {
+		size += len(k) + len(v)
+		if v == nil {
+			size += bucketSize(b.Bucket(k))
+		}
+		return nil
+	})
+	return size
+}
+
+// Store the state of a list after a change like writeList, but only with the
+// tasks that differ from the state before it. See readListChanges.
+func writeListChanges(b *bolt.Bucket, listName string, before, after List) error {
+	changed := after
+	changed.Tasks = make(map[int]*Task)
+	for id, task := range after.Tasks {
+		if old, ok := before.Tasks[id]; !ok || !identicalTask(old, task) {
+			changed.Tasks[id] = task
+		}
+	}
+	return writeList(b, listName, changed)
+}
+
+// Read a list stored with writeListChanges, taking the unchanged tasks from before.
+func readListChanges(b *bolt.Bucket, listName string, before List) (*List, error) {
+	infoBucket, dataBucket, err := openList(b, listName, false)
+	if err != nil {
+		return nil, err
+	}
+	info, err := getInfo(infoBucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get info: %w", err)
+	}
+	taskIdsBytes := dataBucket.Get([]byte("taskIds"))
+	taskBucket := dataBucket.Bucket([]byte("tasks"))
+	if taskIdsBytes == nil || taskBucket == nil {
+		return nil, fmt.Errorf("failed to get data: tasks not found")
+	}
+
+	list := NewList(info.Name)
+	list.Info = info
+	list.TaskIds = bytesToInts(taskIdsBytes)
+	for _, id := range list.TaskIds {
+		if taskBucket := taskBucket.Bucket(itob(id)); taskBucket != nil {
+			task, err := getTask(taskBucket)
+			if err != nil {
+				return nil, err
+			}
+			list.Tasks[id] = &task
+		} else if task, ok := before.Tasks[id]; ok {
+			copied := *task
+			list.Tasks[id] = &copied
+		} else {
+			return nil, fmt.Errorf("task %d not found", id)
+		}
+		list.UsedIds[id] = struct{}{}
+	}
+	list.countTasks()
+	return &list, nil
+}
+
+// whether two tasks are stored the same, down to their timestamps and links
+func identicalTask(a, b *Task) bool {
+	return sameTask(a, b) &&
+		a.Id == b.Id &&
+		a.CreatedAt.Unix() == b.CreatedAt.Unix() &&
+		a.UpdatedAt.Unix() == b.UpdatedAt.Unix() &&
+		a.CompletedAt.Unix() == b.CompletedAt.Unix() &&
+		a.NextId == b.NextId &&
+		a.UUID == b.UUID
+}
+
+// Read the journal entry stored in the given bucket.
+func getJournalEntry(b *bolt.Bucket, id int) (JournalEntry, error) {
+	entry := JournalEntry{
+		Id:        id,
+		Time:      getTime(b, "time"),
+		List:      string(b.Get([]byte("list"))),
+		Operation: string(b.Get([]byte("op"))),
+	}
+	var err error
+	if entry.Before, err = readList(b, "before"); err != nil {
+		return entry, fmt.Errorf("failed to read state before entry %d: %w", id, err)
+	}
+	if entry.Before != nil && b.Bucket([]byte("after")) != nil {
+		entry.After, err = readListChanges(b, "after", *entry.Before)
+	} else {
+		entry.After, err = readList(b, "after")
+	}
+	if err != nil {
+		return entry, fmt.Errorf("failed to read state after entry %d: %w", id, err)
+	}
+	return entry, nil
+}
@@ -0,0 +1,329 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurring tasks spawn their next occurrence when they are completed. The
+// completed occurrence remembers the id of the task it spawned in NextId, so
+// that the chain is only ever extended once per occurrence. A completed task
+// whose link was lost (e.g. because it was imported) does not spawn another
+// occurrence if a pending occurrence of it already exists.
+
+type Frequency int
+
+const (
+	FrequencyNone Frequency = iota
+	FrequencyDaily
+	FrequencyWeekly
+	FrequencyMonthly
+)
+
+var frequencyUnits = []string{"", "day", "week", "month"}
+
+type Recurrence struct {
+	Frequency Frequency
+	Interval  int            // repeat every Interval days, weeks or months. 0 is treated as 1.
+	Weekdays  []time.Weekday // weekly rules only: the days of the week to repeat on, in order
+}
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// check whether the recurrence actually repeats
+func (r Recurrence) IsSet() bool {
+	return r.Frequency != FrequencyNone
+}
+
+func (r Recurrence) interval() int {
+	return max(1, r.Interval)
+}
+
+// Format the rule in the form accepted by ParseRecurrence, e.g. "daily",
+// "every 3 days" or "weekly on mon,thu". The empty string means no recurrence.
+func (r Recurrence) String() string {
+	if !r.IsSet() || int(r.Frequency) >= len(frequencyUnits) {
+		return ""
+	}
+
+	var out string
+	switch {
+	case r.interval() > 1:
+		out = fmt.Sprintf("every %d %ss", r.interval(), frequencyUnits[r.Frequency])
+	case r.Frequency == FrequencyDaily:
+		out = "daily"
+	default:
+		out = frequencyUnits[r.Frequency] + "ly"
+	}
+
+	if r.Frequency == FrequencyWeekly && len(r.Weekdays) > 0 {
+		days := make([]string, len(r.Weekdays))
+		for i, day := range r.Weekdays {
+			days[i] = weekdayNames[day]
+		}
+		out += " on " + strings.Join(days, ",")
+	}
+	return out
+}
+
+// Parse a recurrence rule such as "daily", "weekly", "monthly", "every 3 days",
+// "every 2 weeks" or "weekly on mon,thu". The empty string means no recurrence.
+func ParseRecurrence(s string) (Recurrence, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return Recurrence{}, nil
+	}
+	invalid := fmt.Errorf("invalid recurrence %q - expected e.g. daily, weekly, monthly, every 3 days or weekly on mon,thu", s)
+
+	rule, days, hasDays := strings.Cut(s, " on ")
+	fields := strings.Fields(rule)
+	r := Recurrence{Interval: 1}
+	switch {
+	case len(fields) == 1 && fields[0] == "daily":
+		r.Frequency = FrequencyDaily
+	case len(fields) == 1 && fields[0] == "weekly":
+		r.Frequency = FrequencyWeekly
+	case len(fields) == 1 && fields[0] == "monthly":
+		r.Frequency = FrequencyMonthly
+	case len(fields) >= 2 && fields[0] == "every":
+		unit := fields[len(fields)-1]
+		if len(fields) == 3 {
+			n, err := strconv.Atoi(fields[1])
+			if err != nil || n < 1 {
+				return Recurrence{}, invalid
+			}
+			r.Interval = n
+		} else if len(fields) != 2 {
+			return Recurrence{}, invalid
+		}
+		r.Frequency = parseFrequencyUnit(unit)
+		if r.Frequency == FrequencyNone {
+			return Recurrence{}, invalid
+		}
+	default:
+		return Recurrence{}, invalid
+	}
+
+	if hasDays {
+		if r.Frequency != FrequencyWeekly {
+			return Recurrence{}, fmt.Errorf("invalid recurrence %q - only weekly rules can repeat on given weekdays", s)
+		}
+		weekdays, err := parseWeekdays(days)
+		if err != nil {
+			return Recurrence{}, err
+		}
+		r.Weekdays = weekdays
+	}
+	return r, nil
+}
+
+// the frequency for a unit such as "day" or "weeks", or FrequencyNone if the unit is unknown
+func parseFrequencyUnit(unit string) Frequency {
+	unit = strings.TrimSuffix(unit, "s")
+	for i, name := range frequencyUnits {
+		if i > 0 && unit == name {
+			return Frequency(i)
+		}
+	}
+	return FrequencyNone
+}
+
+// parse a comma separated list of weekdays (e.g. "mon,thu") into weekdays ordered from sunday
+func parseWeekdays(s string) ([]time.Weekday, error) {
+	seen := [7]bool{}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for i, day := range weekdayNames {
+			fullName := strings.ToLower(time.Weekday(i).String())
+			if name == day || len(name) >= 3 && strings.HasPrefix(fullName, name) {
+				seen[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid weekday %q - expected one of %s", name, strings.Join(weekdayNames, ", "))
+		}
+	}
+	weekdays := []time.Weekday{}
+	for i, ok := range seen {
+		if ok {
+			weekdays = append(weekdays, time.Weekday(i))
+		}
+	}
+	return weekdays, nil
+}
+
+// The first date of the rule after the given one. The time of day is kept.
+func (r Recurrence) Next(from time.Time) time.Time {
+	n := r.interval()
+	switch r.Frequency {
+	case FrequencyDaily:
+		return from.AddDate(0, 0, n)
+	case FrequencyWeekly:
+		if len(r.Weekdays) == 0 {
+			return from.AddDate(0, 0, 7*n)
+		}
+		// weeks start on monday, so a rule on "sun,mon" moves from sunday to the next week
+		for i := 1; i <= 7; i++ {
+			next := from.AddDate(0, 0, i)
+			if !r.repeatsOn(next.Weekday()) {
+				continue
+			}
+			if weekIndex(next.Weekday()) <= weekIndex(from.Weekday()) {
+				next = next.AddDate(0, 0, 7*(n-1)) // skip the weeks in between
+			}
+			return next
+		}
+		return from.AddDate(0, 0, 7*n)
+	case FrequencyMonthly:
+		return addMonths(from, n)
+	}
+	return from
+}
+
+func (r Recurrence) repeatsOn(day time.Weekday) bool {
+	for _, d := range r.Weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// position of the weekday in a week starting on monday
+func weekIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+// add months to t, clamping the day so that e.g. january 31st becomes the last day of february
+func addMonths(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	firstOfMonth := time.Date(year, month+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(day, lastDay)-1)
+}
+
+// Spawn the next occurrence of every completed recurring task that has not
+// spawned one yet. Subtasks are copied along with their parent as pending tasks.
+func (l *List) spawnOccurrences(now time.Time) {
+	children := l.childMap()
+	order := []int{}
+	for _, rootId := range children[0] {
+		order = appendSubtree(children, rootId, order)
+	}
+	for _, id := range order {
+		task := l.Tasks[id]
+		if task.Done && task.Recurrence.IsSet() && task.NextId == 0 && !l.hasPendingOccurrence(task) {
+			l.spawnNext(task, now)
+		}
+	}
+}
+
+// check if a pending task with the same description and recurrence rule exists
+func (l *List) hasPendingOccurrence(task *Task) bool {
+	rule := task.Recurrence.String()
+	for _, other := range l.Tasks {
+		if !other.Done && other.Description == task.Description && other.Recurrence.String() == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// add a pending copy of the task and its subtasks right after the task's
+// subtree, with its dates advanced according to its recurrence rule
+func (l *List) spawnNext(task *Task, now time.Time) {
+	ids := append([]int{task.Id}, l.DescendantIds(task.Id)...)
+	inSubtree := make(map[int]struct{}, len(ids))
+	for _, id := range ids {
+		inSubtree[id] = struct{}{}
+	}
+	insertAt := len(l.TaskIds)
+	for i, id := range l.TaskIds {
+		if _, ok := inSubtree[id]; ok {
+			insertAt = i + 1
+		}
+	}
+
+	// subtasks are moved by as much as the task itself
+	due, scheduled := task.Recurrence.nextDates(task, now)
+	var shift time.Duration
+	if !task.Due.IsZero() {
+		shift = due.Sub(task.Due)
+	} else if !task.Scheduled.IsZero() {
+		shift = scheduled.Sub(task.Scheduled)
+	}
+
+	copies := map[int]int{}
+	for _, id := range ids {
+		original := l.Tasks[id]
+		next, err := l.NewTask(original.Description, false)
+		if err != nil {
+			return
+		}
+		next.Priority = original.Priority
+		next.Tags = append([]string{}, original.Tags...)
+		next.Notes = original.Notes
+		next.Recurrence = original.Recurrence
+		if parent, ok := copies[original.ParentId]; ok {
+			next.ParentId = parent
+		} else {
+			next.ParentId = original.ParentId
+		}
+		if id == task.Id {
+			next.Due, next.Scheduled = due, scheduled
+		} else {
+			next.Due, next.Scheduled = shiftDate(original.Due, shift), shiftDate(original.Scheduled, shift)
+		}
+		if err := l.Insert(next, insertAt); err != nil {
+			return
+		}
+		insertAt++
+		copies[id] = next.Id
+		original.NextId = next.Id
+	}
+	l.syncAncestors(copies[task.Id], false)
+}
+
+// move an optional date by the given duration
+func shiftDate(t time.Time, d time.Duration) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.Add(d)
+}
+
+// the due and scheduled dates of the occurrence after the given task. Tasks
+// without any dates are due one period after they were completed.
+func (r Recurrence) nextDates(task *Task, now time.Time) (due, scheduled time.Time) {
+	switch {
+	case !task.Due.IsZero():
+		due = r.Next(task.Due)
+		if !task.Scheduled.IsZero() {
+			scheduled = task.Scheduled.Add(due.Sub(task.Due))
+		}
+	case !task.Scheduled.IsZero():
+		scheduled = r.Next(task.Scheduled)
+	default:
+		due = r.Next(StartOfDay(now))
+	}
+	return due, scheduled
+}
+
+// Remove the occurrence spawned by the task if it has not been touched since,
+// so that un-completing a task by mistake does not leave a duplicate behind.
+func (l *List) retractNext(task *Task) {
+	next, ok := l.Tasks[task.NextId]
+	if ok && !next.Done && next.UpdatedAt.Equal(next.CreatedAt) {
+		for _, id := range append(l.DescendantIds(next.Id), next.Id) {
+			l.RemoveTask(id)
+		}
+	}
+	for _, id := range append(l.DescendantIds(task.Id), task.Id) {
+		l.Tasks[id].NextId = 0
+	}
+}
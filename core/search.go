@@ -0,0 +1,72 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// options that control which tasks a search matches
+type SearchOptions struct {
+	Regex         bool // treat the query as a regular expression instead of plain text
+	CaseSensitive bool
+	OnlyDone      bool
+	OnlyPending   bool
+}
+
+// a task found by a search along with its position, see List.TaskAtPosition
+type SearchMatch struct {
+	Position int
+	Task     *Task
+}
+
+// the matches of a search in one list
+type SearchResult struct {
+	List    string
+	Matches []SearchMatch
+}
+
+// Compile a search query into a pattern. Plain text queries match literally.
+func CompileSearch(query string, opts SearchOptions) (*regexp.Regexp, error) {
+	if opts.Regex {
+		if _, err := regexp.Compile(query); err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %v", err)
+		}
+	} else {
+		query = regexp.QuoteMeta(query)
+	}
+	if !opts.CaseSensitive {
+		query = "(?i)" + query
+	}
+	pattern, err := regexp.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %v", err)
+	}
+	return pattern, nil
+}
+
+// whether the description, notes or tags of the task match the pattern
+func (t *Task) Matches(pattern *regexp.Regexp) bool {
+	if pattern.MatchString(t.Description) || pattern.MatchString(t.Notes) {
+		return true
+	}
+	for _, tag := range t.Tags {
+		if pattern.MatchString(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// the tasks of the list that match the pattern and options, in display order
+func (l *List) Search(pattern *regexp.Regexp, opts SearchOptions) []SearchMatch {
+	matches := []SearchMatch{}
+	for i, task := range l.DisplayOrder() {
+		if (opts.OnlyDone && !task.Done) || (opts.OnlyPending && task.Done) {
+			continue
+		}
+		if task.Matches(pattern) {
+			matches = append(matches, SearchMatch{Position: i + 1, Task: task})
+		}
+	}
+	return matches
+}
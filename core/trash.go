@@ -0,0 +1,107 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// kinds of items in the trash
+const (
+	TrashedList  = "list"  // a deleted list
+	TrashedTasks = "tasks" // completed tasks removed from a list by cleaning it
+)
+
+// Something that was deleted and can still be restored. For cleaned tasks,
+// List holds only the removed tasks under the name of the list they came from.
+type TrashItem struct {
+	Id   int
+	Time time.Time // when the item was moved to the trash
+	Kind string
+	List List
+}
+
+// e.g. "list with 3 tasks"
+func (i TrashItem) Summary() string {
+	if i.Kind == TrashedList {
+		return fmt.Sprintf("list with %d tasks", len(i.List.Tasks))
+	}
+	return fmt.Sprintf("%d cleaned tasks", len(i.List.Tasks))
+}
+
+// Add tasks that were removed from the list back at its end. Tasks keep their
+// id unless it has been taken since, and their parent if it is still around.
+func (l *List) restoreTasks(removed List) error {
+	newIds := map[int]int{}
+	for _, oldId := range removed.TaskIds {
+		task := *removed.Tasks[oldId]
+		task.Tags = append([]string{}, task.Tags...)
+		if _, taken := l.Tasks[task.Id]; taken {
+			id, err := l.generateTaskId()
+			if err != nil {
+				return err
+			}
+			task.Id = id
+		}
+		newIds[oldId] = task.Id
+
+		if parentId, ok := newIds[task.ParentId]; ok {
+			task.ParentId = parentId
+		} else if _, ok := l.Tasks[task.ParentId]; !ok {
+			task.ParentId = 0
+		}
+		if _, ok := l.Tasks[task.NextId]; !ok {
+			task.NextId = 0
+		}
+		if err := l.AddTask(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// -------------------------------- storage ---------------------------------
+
+// Move a deleted list or the tasks removed from a list into the trash and return
+// the bucket of the new trash item.
+func addToTrash(tx *bolt.Tx, kind string, list List) (*bolt.Bucket, error) {
+	trash := tx.Bucket([]byte("trash"))
+	if trash == nil {
+		return nil, fmt.Errorf("trash bucket not found - likely issue with database initialization")
+	}
+
+	seq, err := trash.NextSequence()
+	if err != nil {
+		return nil, err
+	}
+	item, err := trash.CreateBucket(itob(int(seq)))
+	if err != nil {
+		return nil, err
+	}
+	if err := putTime(item, "time", time.Now()); err != nil {
+		return nil, err
+	}
+	if err := item.Put([]byte("kind"), []byte(kind)); err != nil {
+		return nil, err
+	}
+	return item, writeList(item, "content", list)
+}
+
+// Read the trash item stored in the given bucket.
+func getTrashItem(b *bolt.Bucket, id int) (TrashItem, error) {
+	item := TrashItem{
+		Id:   id,
+		Time: getTime(b, "time"),
+		Kind: string(b.Get([]byte("kind"))),
+	}
+	list, err := readList(b, "content")
+	if err != nil {
+		return item, fmt.Errorf("failed to read trash item %d: %w", id, err)
+	}
+	if list == nil {
+		return item, fmt.Errorf("trash item %d is empty", id)
+	}
+	item.List = *list
+	return item, nil
+}
@@ -0,0 +1,235 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// Tasks can be nested under other tasks by setting their ParentId to the id of
+// the parent task (0 means top-level). TaskIds stays a flat slice and siblings
+// are ordered by their position in it. A task whose parent no longer exists is
+// treated as a top-level task so that lists never lose tasks.
+
+// the id of the task's parent, or 0 if it is a top-level task
+func (l *List) parentId(task *Task) int {
+	if task.ParentId == task.Id {
+		return 0
+	}
+	if _, ok := l.Tasks[task.ParentId]; !ok {
+		return 0
+	}
+	return task.ParentId
+}
+
+// map from parent id (0 for top-level) to the ids of its direct children in TaskIds order
+func (l *List) childMap() map[int][]int {
+	children := make(map[int][]int)
+	for _, id := range l.TaskIds {
+		task, ok := l.Tasks[id]
+		if !ok {
+			continue
+		}
+		parent := l.parentId(task)
+		children[parent] = append(children[parent], id)
+	}
+	return children
+}
+
+// append the ids of the task and all of its descendants to out, parents before children
+func appendSubtree(children map[int][]int, taskId int, out []int) []int {
+	out = append(out, taskId)
+	for _, childId := range children[taskId] {
+		out = appendSubtree(children, childId, out)
+	}
+	return out
+}
+
+// ids of the direct children of the task in TaskIds order. Use 0 for top-level tasks.
+func (l *List) ChildIds(taskId int) []int {
+	return l.childMap()[taskId]
+}
+
+// ids of every descendant of the task, parents before children
+func (l *List) DescendantIds(taskId int) []int {
+	return appendSubtree(l.childMap(), taskId, nil)[1:]
+}
+
+// check whether the task has any children
+func (l *List) HasChildren(taskId int) bool {
+	return len(l.ChildIds(taskId)) > 0
+}
+
+// number of ancestors of the task, 0 for top-level tasks
+func (l *List) Depth(taskId int) int {
+	depth := 0
+	task, ok := l.Tasks[taskId]
+	for ok && depth <= len(l.Tasks) { // the bound protects against cycles
+		parent := l.parentId(task)
+		if parent == 0 {
+			break
+		}
+		depth++
+		task, ok = l.Tasks[parent]
+	}
+	return depth
+}
+
+// number of completed descendants and the total number of descendants of the task
+func (l *List) Progress(taskId int) (done, total int) {
+	for _, id := range l.DescendantIds(taskId) {
+		total++
+		if l.Tasks[id].Done {
+			done++
+		}
+	}
+	return done, total
+}
+
+// Set the parent of the task without changing its position in TaskIds. Use 0
+// to make it a top-level task. A completed parent becomes pending again if the
+// task is pending.
+func (l *List) SetParent(taskId, parentId int) error {
+	task, ok := l.Tasks[taskId]
+	if !ok {
+		return fmt.Errorf("tried setting parent of non-existent task id %d in list %s", taskId, l.Info.Name)
+	}
+	if parentId != 0 {
+		if err := l.checkNewParent(taskId, parentId); err != nil {
+			return err
+		}
+	}
+	if task.ParentId != parentId {
+		task.ParentId = parentId
+		l.touch(task)
+	}
+	l.syncAncestors(taskId, false)
+	return nil
+}
+
+// Nest the task (along with its own children) under the given parent as its
+// last child. A completed parent becomes pending again if the task is pending.
+func (l *List) MoveUnder(taskId, parentId int) error {
+	task, ok := l.Tasks[taskId]
+	if !ok {
+		return fmt.Errorf("tried moving non-existent task id %d in list %s", taskId, l.Info.Name)
+	}
+	if err := l.checkNewParent(taskId, parentId); err != nil {
+		return err
+	}
+
+	l.moveSubtree(taskId, parentId)
+	task.ParentId = parentId
+	l.touch(task)
+	l.syncAncestors(taskId, false)
+	return nil
+}
+
+// check that the task with the given id can be nested under parentId
+func (l *List) checkNewParent(taskId, parentId int) error {
+	if _, ok := l.Tasks[parentId]; !ok {
+		return fmt.Errorf("tried nesting task under non-existent task id %d in list %s", parentId, l.Info.Name)
+	}
+	if parentId == taskId {
+		return fmt.Errorf("cannot nest task id %d under itself", taskId)
+	}
+	for _, id := range l.DescendantIds(taskId) {
+		if id == parentId {
+			return fmt.Errorf("cannot nest task id %d under its own descendant %d", taskId, parentId)
+		}
+	}
+	return nil
+}
+
+// Move the task (along with its own children) out of its parent so that it
+// becomes the next sibling of its former parent.
+func (l *List) Outdent(taskId int) error {
+	task, ok := l.Tasks[taskId]
+	if !ok {
+		return fmt.Errorf("tried outdenting non-existent task id %d in list %s", taskId, l.Info.Name)
+	}
+	parentId := l.parentId(task)
+	if parentId == 0 {
+		return fmt.Errorf("task id %d is already a top-level task", taskId)
+	}
+
+	l.moveSubtree(taskId, parentId)
+	task.ParentId = l.parentId(l.Tasks[parentId])
+	l.touch(task)
+	l.syncAncestors(taskId, false)
+	return nil
+}
+
+// move the ids of the task and its descendants in TaskIds so that they come
+// right after the last descendant of the task with the id afterId
+func (l *List) moveSubtree(taskId, afterId int) {
+	children := l.childMap()
+	subtree := appendSubtree(children, taskId, nil)
+	inSubtree := make(map[int]struct{}, len(subtree))
+	for _, id := range subtree {
+		inSubtree[id] = struct{}{}
+	}
+	afterSubtree := make(map[int]struct{})
+	for _, id := range appendSubtree(children, afterId, nil) {
+		afterSubtree[id] = struct{}{}
+	}
+
+	// remove the subtree and find the last position of afterId's subtree
+	remaining := make([]int, 0, len(l.TaskIds))
+	insertAt := 0
+	for _, id := range l.TaskIds {
+		if _, ok := inSubtree[id]; ok {
+			continue
+		}
+		remaining = append(remaining, id)
+		if _, ok := afterSubtree[id]; ok {
+			insertAt = len(remaining)
+		}
+	}
+
+	l.TaskIds = append(remaining[:insertAt], append(subtree, remaining[insertAt:]...)...)
+}
+
+// set the completion status of the task and update the list info
+func (l *List) setDone(task *Task, done bool) {
+	if task.Done == done {
+		return
+	}
+	task.Done = done
+	l.touch(task)
+	if done {
+		task.CompletedAt = task.UpdatedAt
+		l.Info.NumDone++
+		l.Info.NumPending--
+	} else {
+		task.CompletedAt = time.Time{}
+		l.Info.NumDone--
+		l.Info.NumPending++
+	}
+}
+
+// Make the ancestors of the task reflect the completion of their children: a
+// parent with a pending child is pending, and if allowComplete is set, a parent
+// whose children are all done is done.
+func (l *List) syncAncestors(taskId int, allowComplete bool) {
+	task, ok := l.Tasks[taskId]
+	for depth := 0; ok && depth <= len(l.Tasks); depth++ {
+		parentId := l.parentId(task)
+		if parentId == 0 {
+			return
+		}
+		parent := l.Tasks[parentId]
+		allDone := true
+		for _, childId := range l.ChildIds(parentId) {
+			if !l.Tasks[childId].Done {
+				allDone = false
+				break
+			}
+		}
+		if !allDone {
+			l.setDone(parent, false)
+		} else if allowComplete {
+			l.setDone(parent, true)
+		}
+		task = parent
+	}
+}
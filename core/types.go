@@ -1,17 +1,170 @@
 package core
 
 import (
+	"crypto/rand"
 	"fmt"
-	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"google.golang.org/genai"
 )
 
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = []string{"none", "low", "medium", "high", "urgent"}
+
+func (p Priority) String() string {
+	if p < PriorityNone || p > PriorityUrgent {
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+	return priorityNames[p]
+}
+
+// parse a priority from its name (e.g. "high"). The empty string is PriorityNone.
+func ParsePriority(s string) (Priority, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return PriorityNone, nil
+	}
+	for i, name := range priorityNames {
+		if s == name {
+			return Priority(i), nil
+		}
+	}
+	return PriorityNone, fmt.Errorf("invalid priority %q - expected one of %s", s, strings.Join(priorityNames, ", "))
+}
+
+// the next priority level, capped at PriorityUrgent
+func (p Priority) Raise() Priority {
+	return min(p+1, PriorityUrgent)
+}
+
+// the previous priority level, capped at PriorityNone
+func (p Priority) Lower() Priority {
+	return max(p-1, PriorityNone)
+}
+
 type Task struct {
 	Id          int
+	ParentId    int // id of the parent task, 0 for top-level tasks
 	Description string
 	Done        bool
+	Priority    Priority
+	Tags        []string  // explicit tags, see AllTags for tags written in the description
+	Due         time.Time // zero value means no due date
+	Scheduled   time.Time // zero value means no scheduled date
+	Notes       string    // free-form, possibly multi-line notes
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt time.Time // zero value while the task is pending
+	Recurrence  Recurrence
+	NextId      int    // id of the occurrence spawned when this recurring task was completed
+	UUID        string // identifies the task in other apps, e.g. Taskwarrior and calendars. Set when the task is created
+}
+
+// matches #tag and @tag words in a description
+var tagPattern = regexp.MustCompile(`(?:^|\s)([#@][\w\-./]+)`)
+
+// Normalize a tag so that "Backend", "#backend" and "#Backend " are the same tag.
+// Tags without a leading # or @ are treated as # tags.
+func NormalizeTag(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return ""
+	}
+	if tag[0] != '#' && tag[0] != '@' {
+		tag = "#" + tag
+	}
+	return tag
+}
+
+// extract the #tag and @tag words from a description
+func ParseTags(description string) []string {
+	tags := []string{}
+	for _, match := range tagPattern.FindAllStringSubmatch(description, -1) {
+		tags = append(tags, NormalizeTag(match[1]))
+	}
+	return tags
+}
+
+// all tags of the task, both explicit and parsed from the description, sorted and without duplicates
+func (t *Task) AllTags() []string {
+	seen := map[string]struct{}{}
+	out := []string{}
+	for _, tag := range append(ParseTags(t.Description), t.Tags...) {
+		tag = NormalizeTag(tag)
+		if _, ok := seen[tag]; ok || tag == "" {
+			continue
+		}
+		seen[tag] = struct{}{}
+		out = append(out, tag)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Check if the task carries the given tag. A tag without a leading # or @
+// matches both kinds, so "alice" matches "#alice" and "@alice".
+func (t *Task) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return false
+	}
+	for _, own := range t.AllTags() {
+		if own == tag || own[1:] == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// a task is overdue if it is still pending and its due date is before today
+func (t *Task) IsOverdue(now time.Time) bool {
+	if t.Done || t.Due.IsZero() {
+		return false
+	}
+	return t.Due.Before(StartOfDay(now))
+}
+
+// short human readable summary of the task's dates and recurrence, or "" if it has none
+func (t *Task) DateSummary() string {
+	parts := []string{}
+	if !t.Scheduled.IsZero() {
+		parts = append(parts, "scheduled "+FormatDate(t.Scheduled))
+	}
+	if !t.Due.IsZero() {
+		parts = append(parts, "due "+FormatDate(t.Due))
+	}
+	if t.Recurrence.IsSet() {
+		parts = append(parts, t.Recurrence.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// short human readable summary of when the task was created, last modified and completed
+func (t *Task) HistorySummary() string {
+	parts := []string{}
+	if !t.CreatedAt.IsZero() {
+		parts = append(parts, "created "+t.CreatedAt.Format(TimestampLayout))
+	}
+	if updated := t.UpdatedAt.Format(TimestampLayout); !t.UpdatedAt.IsZero() && updated != t.CreatedAt.Format(TimestampLayout) {
+		parts = append(parts, "updated "+updated)
+	}
+	if !t.CompletedAt.IsZero() {
+		parts = append(parts, "completed "+t.CompletedAt.Format(TimestampLayout))
+	}
+	return strings.Join(parts, ", ")
 }
 
 type ListInfo struct {
@@ -19,6 +172,21 @@ type ListInfo struct {
 	NumDone    int
 	NumPending int
 	NumTasks   int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	NextId     int // the id given to the next new task. Ids are never reused within a list.
+}
+
+// short human readable summary of when the list was created and last modified
+func (i ListInfo) HistorySummary() string {
+	parts := []string{}
+	if !i.CreatedAt.IsZero() {
+		parts = append(parts, "created "+i.CreatedAt.Format(TimestampLayout))
+	}
+	if !i.UpdatedAt.IsZero() {
+		parts = append(parts, "updated "+i.UpdatedAt.Format(TimestampLayout))
+	}
+	return strings.Join(parts, ", ")
 }
 
 type List struct {
@@ -35,6 +203,13 @@ type List struct {
 //					{
 //						"description": string,
 //						"done": bool,
+//						"tasks": [ // optional sub-steps
+//							{
+//								"description": string,
+//								"done": bool,
+//							},
+//							...
+//						]
 //					},
 //					...
 //				]
@@ -54,6 +229,16 @@ var geminiSchema = &genai.Schema{
 					Properties: map[string]*genai.Schema{
 						"description": {Type: genai.TypeString},
 						"done":        {Type: genai.TypeBoolean},
+						"tasks": {
+							Type: genai.TypeArray,
+							Items: &genai.Schema{
+								Type: genai.TypeObject,
+								Properties: map[string]*genai.Schema{
+									"description": {Type: genai.TypeString},
+									"done":        {Type: genai.TypeBoolean},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -67,9 +252,13 @@ var GeminiConfig = &genai.GenerateContentConfig{
 }
 
 func NewList(name string) List {
+	now := time.Now()
 	return List{
 		Info: ListInfo{
-			Name: name,
+			Name:      name,
+			CreatedAt: now,
+			UpdatedAt: now,
+			NextId:    1,
 		},
 		TaskIds: []int{},
 		Tasks:   make(map[int]*Task),
@@ -77,19 +266,80 @@ func NewList(name string) List {
 	}
 }
 
+// set the task counts of the list info from its tasks
+func (l *List) countTasks() {
+	l.Info.NumTasks = len(l.Tasks)
+	l.Info.NumDone = 0
+	l.Info.NumPending = 0
+	for _, task := range l.Tasks {
+		if task.Done {
+			l.Info.NumDone++
+		} else {
+			l.Info.NumPending++
+		}
+	}
+}
+
+// deep copy of the list that shares no memory with the original
+func (l List) Clone() List {
+	clone := l
+	clone.TaskIds = append([]int{}, l.TaskIds...)
+	clone.Tasks = make(map[int]*Task, len(l.Tasks))
+	for id, task := range l.Tasks {
+		copied := *task
+		copied.Tags = append([]string{}, task.Tags...)
+		copied.Recurrence.Weekdays = append([]time.Weekday{}, task.Recurrence.Weekdays...)
+		clone.Tasks[id] = &copied
+	}
+	clone.UsedIds = make(map[int]struct{}, len(l.UsedIds))
+	for id := range l.UsedIds {
+		clone.UsedIds[id] = struct{}{}
+	}
+	return clone
+}
+
+// Hand out the next task id of the list. Ids are small, increase with every
+// new task and are never reused, so they stay valid when tasks are reordered.
 func (l *List) generateTaskId() (int, error) {
-	numAttempts := 0
-	for {
-		id := rand.Int()
+	l.Info.NextId = max(l.Info.NextId, 1) // 0 is reserved for "no parent"
+	for range len(l.UsedIds) + 1 {
+		id := l.Info.NextId
+		l.Info.NextId++
 		if _, ok := l.UsedIds[id]; !ok {
 			l.UsedIds[id] = struct{}{}
 			return id, nil
 		}
-		numAttempts++
-		if numAttempts > 100 {
-			return -1, fmt.Errorf("failed to generate unique task id - make sure that they are being deleted from UsedIds correctly")
-		}
 	}
+	return -1, fmt.Errorf("failed to generate unique task id - make sure that they are being deleted from UsedIds correctly")
+}
+
+// mark the id of a task that is added to the list as used
+func (l *List) reserveTaskId(id int) {
+	l.UsedIds[id] = struct{}{}
+	l.Info.NextId = max(l.Info.NextId, id+1)
+}
+
+// Give every task a new id counting up from 1 in TaskIds order, keeping
+// parents and recurrence links intact. Used to migrate lists created
+// before task ids were short.
+func (l *List) renumberTasks() {
+	newIds := make(map[int]int, len(l.TaskIds))
+	for i, id := range l.TaskIds {
+		newIds[id] = i + 1
+	}
+	tasks := make(map[int]*Task, len(l.Tasks))
+	l.UsedIds = make(map[int]struct{}, len(l.Tasks))
+	for i, oldId := range l.TaskIds {
+		task := l.Tasks[oldId]
+		task.Id = newIds[oldId]
+		task.ParentId = newIds[task.ParentId] // missing parents become 0
+		task.NextId = newIds[task.NextId]
+		tasks[task.Id] = task
+		l.TaskIds[i] = task.Id
+		l.UsedIds[task.Id] = struct{}{}
+	}
+	l.Tasks = tasks
+	l.Info.NextId = len(l.TaskIds) + 1
 }
 
 // make new task with the given description and completion status without adding it to the list.
@@ -98,13 +348,45 @@ func (l *List) NewTask(description string, done bool) (Task, error) {
 	if err != nil {
 		return Task{}, err
 	}
+	return newTask(id, description, done), nil
+}
+
+// Make a new task like NewTask, but with the given id instead of the next
+// one. Used to restore backups, where tasks must keep their ids.
+func (l *List) NewTaskWithId(id int, description string, done bool) (Task, error) {
+	if id < 1 {
+		return Task{}, fmt.Errorf("invalid task id %d - ids start at 1", id)
+	}
+	if _, ok := l.UsedIds[id]; ok {
+		return Task{}, fmt.Errorf("task id %d is used more than once", id)
+	}
+	l.reserveTaskId(id)
+	return newTask(id, description, done), nil
+}
+
+func newTask(id int, description string, done bool) Task {
+	now := time.Now()
 	task := Task{
 		Id:          id,
 		Description: description,
 		Done:        done,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		UUID:        NewUUID(),
+	}
+	if done {
+		task.CompletedAt = now
 	}
+	return task
+}
 
-	return task, nil
+// a random (version 4) UUID
+func NewUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
 }
 
 // add a task to the list.
@@ -114,6 +396,8 @@ func (l *List) AddTask(task Task) error {
 	}
 	l.Tasks[task.Id] = &task
 	l.TaskIds = append(l.TaskIds, task.Id)
+	l.reserveTaskId(task.Id)
+	l.touch(nil)
 	l.Info.NumTasks++
 	if task.Done {
 		l.Info.NumDone++
@@ -133,6 +417,8 @@ func (l *List) Insert(task Task, index int) error {
 	}
 	l.Tasks[task.Id] = &task
 	l.TaskIds = append(l.TaskIds[:index], append([]int{task.Id}, l.TaskIds[index:]...)...)
+	l.reserveTaskId(task.Id)
+	l.touch(nil)
 	l.Info.NumTasks++
 	if task.Done {
 		l.Info.NumDone++
@@ -169,12 +455,17 @@ func (l *List) InsertNewTask(description string, index int) (int, error) {
 	return task.Id, nil
 }
 
-// remove the task with the given id from the list and update meta data
+// remove the task with the given id from the list and update meta data.
+// Children of the task are moved up to the task's parent.
 func (l *List) RemoveTask(taskId int) error {
 	if task, ok := l.Tasks[taskId]; ok {
+		for _, childId := range l.ChildIds(taskId) {
+			l.Tasks[childId].ParentId = l.parentId(task)
+		}
 		delete(l.Tasks, taskId)
 		delete(l.UsedIds, taskId)
 		l.TaskIds = RemoveIntFromSlice(l.TaskIds, taskId)
+		l.touch(nil)
 		l.Info.NumTasks--
 		if task.Done {
 			l.Info.NumDone--
@@ -187,26 +478,48 @@ func (l *List) RemoveTask(taskId int) error {
 	return nil
 }
 
+// Record that the list was modified just now. If task is not nil, the
+// task is marked as modified as well.
+func (l *List) touch(task *Task) {
+	now := time.Now()
+	l.Info.UpdatedAt = now
+	if task != nil {
+		task.UpdatedAt = now
+	}
+}
+
 // update the description of the task with the given id
 func (l *List) EditTaskDescription(taskId int, newDescription string) error {
 	if task, ok := l.Tasks[taskId]; ok {
 		task.Description = newDescription
+		l.touch(task)
 	} else {
 		return fmt.Errorf("tried editing non-existent task id %d in list %s", taskId, l.Info.Name)
 	}
 	return nil
 }
 
-// toggle the completion status of the task with the given id
+// toggle the completion status of the task with the given id. Its
+// descendants are given the same status and its ancestors are updated
+// so that a parent is done exactly when all of its children are.
+// Completing a recurring task spawns its next occurrence, and reopening
+// it removes that occurrence again unless it was changed in the meantime.
 func (l *List) ToggleCompletion(taskId int) error {
 	if task, ok := l.Tasks[taskId]; ok {
-		task.Done = !task.Done
-		if task.Done {
-			l.Info.NumDone++
-			l.Info.NumPending--
+		done := !task.Done
+		subtree := append([]int{taskId}, l.DescendantIds(taskId)...)
+		for _, id := range subtree {
+			l.setDone(l.Tasks[id], done)
+		}
+		l.syncAncestors(taskId, true)
+		if done {
+			l.spawnOccurrences(time.Now())
 		} else {
-			l.Info.NumDone--
-			l.Info.NumPending++
+			for _, id := range subtree {
+				if task, ok := l.Tasks[id]; ok && task.Recurrence.IsSet() && task.NextId != 0 {
+					l.retractNext(task)
+				}
+			}
 		}
 	} else {
 		return fmt.Errorf("tried toggling non-existent task id %d in list %s", taskId, l.Info.Name)
@@ -214,22 +527,213 @@ func (l *List) ToggleCompletion(taskId int) error {
 	return nil
 }
 
+// update the priority of the task with the given id
+func (l *List) SetPriority(taskId int, priority Priority) error {
+	if task, ok := l.Tasks[taskId]; ok {
+		task.Priority = priority
+		l.touch(task)
+	} else {
+		return fmt.Errorf("tried setting priority of non-existent task id %d in list %s", taskId, l.Info.Name)
+	}
+	return nil
+}
+
+// replace the notes of the task with the given id
+func (l *List) SetNotes(taskId int, notes string) error {
+	if task, ok := l.Tasks[taskId]; ok {
+		task.Notes = strings.TrimRight(notes, "\n")
+		l.touch(task)
+	} else {
+		return fmt.Errorf("tried setting notes of non-existent task id %d in list %s", taskId, l.Info.Name)
+	}
+	return nil
+}
+
+// update the recurrence rule of the task with the given id
+func (l *List) SetRecurrence(taskId int, recurrence Recurrence) error {
+	if task, ok := l.Tasks[taskId]; ok {
+		task.Recurrence = recurrence
+		l.touch(task)
+	} else {
+		return fmt.Errorf("tried setting recurrence of non-existent task id %d in list %s", taskId, l.Info.Name)
+	}
+	return nil
+}
+
+// Remove every completed task from the list and return how many were removed.
+// Recurring tasks spawn their next occurrence first so that the chain is not lost,
+// and subtasks that are still pending are moved up to the closest remaining ancestor.
+func (l *List) RemoveCompleted() int {
+	l.spawnOccurrences(time.Now())
+	numRemoved := 0
+	for _, id := range append([]int{}, l.TaskIds...) {
+		if task, ok := l.Tasks[id]; ok && task.Done {
+			l.RemoveTask(id)
+			numRemoved++
+		}
+	}
+	return numRemoved
+}
+
+// options that control how a list is printed by Render
+type RenderOptions struct {
+	ByPriority bool    // sort pending tasks by priority instead of their manual order
+	Tag        string  // only show tasks with this tag, see Task.HasTag
+	Filter     *Filter // only show tasks matching this filter, see ParseFilter
+	Verbose    bool    // print the history and notes of each task below it
+	Numbered   bool    // print the position of each task, see List.TaskAtPosition
+	Ids        bool    // print the id of each task
+}
+
+// The tasks of the list in the order `listly show` prints them by default:
+// pending tasks first, then completed ones, each followed by its subtasks.
+func (l *List) DisplayOrder() []*Task {
+	completed, pending := SplitByCompletion(*l)
+	return append(pending, completed...)
+}
+
+// The task at the given 1-based position of DisplayOrder. Positions do not
+// change when the output is sorted or filtered.
+func (l *List) TaskAtPosition(position int) (*Task, error) {
+	order := l.DisplayOrder()
+	if position < 1 || position > len(order) {
+		return nil, fmt.Errorf("no task at position %d in list %s - it has %d tasks", position, l.Info.Name, len(order))
+	}
+	return order[position-1], nil
+}
+
 func (l *List) String() string {
+	return l.Render(RenderOptions{})
+}
+
+func (l *List) Render(opts RenderOptions) string {
 	listName := l.Info.Name
 	if len(l.Tasks) == 0 {
 		return fmt.Sprintf("No tasks found in list '%s'\n", listName)
 	}
 
 	out := ""
+	now := time.Now()
 	completed, pending := SplitByCompletion(*l)
+	if opts.ByPriority {
+		pending = SortByPriority(pending)
+	}
+	if opts.Tag != "" {
+		completed = FilterByTag(completed, opts.Tag)
+		pending = FilterByTag(pending, opts.Tag)
+		if len(completed)+len(pending) == 0 {
+			return fmt.Sprintf("No tasks tagged %q found in list '%s'\n", opts.Tag, listName)
+		}
+	}
+	if opts.Filter != nil {
+		completed = FilterTasks(completed, opts.Filter)
+		pending = FilterTasks(pending, opts.Filter)
+		if len(completed)+len(pending) == 0 {
+			return fmt.Sprintf("No tasks matching %q found in list '%s'\n", opts.Filter, listName)
+		}
+	}
 	out += fmt.Sprintf("%s\n", listName)
 	out += fmt.Sprint(strings.Repeat("=", max(10, len(listName))) + "\n")
-	for _, task := range pending {
-		out += fmt.Sprintf("   [ ] %s\n", task.Description)
+	if opts.Verbose {
+		if history := l.Info.HistorySummary(); history != "" {
+			out += fmt.Sprintf("(%s)\n\n", history)
+		}
 	}
-	for _, task := range completed {
-		out += fmt.Sprintf("   [x] %s\n", task.Description)
+	idWidth := max(3, len(strconv.Itoa(l.Info.NextId-1)))
+	positions := map[int]int{}
+	if opts.Numbered {
+		for i, task := range l.DisplayOrder() {
+			positions[task.Id] = i + 1
+		}
+	}
+	for _, task := range append(pending, completed...) {
+		margin, box := "   ", "[ ]"
+		if opts.Numbered || opts.Ids {
+			columns := []string{}
+			if opts.Numbered {
+				columns = append(columns, fmt.Sprintf("%3d", positions[task.Id]))
+			}
+			if opts.Ids {
+				columns = append(columns, fmt.Sprintf("%*d", idWidth, task.Id))
+			}
+			margin = strings.Join(columns, " ") + " "
+		}
+		if task.Done {
+			box = "[x]"
+		}
+		out += fmt.Sprintf("%s%s%s %s%s%s%s%s\n", margin, l.indent(task), box, priorityPrefix(task), task.Description, l.progressSuffix(task), tagSuffix(task), dateSuffix(task, now))
+		if opts.Verbose {
+			out += l.detailsBlock(task, len(margin)+4)
+		}
 	}
 
 	return out
 }
+
+// indentation that shows how deeply the task is nested
+func (l *List) indent(task *Task) string {
+	return strings.Repeat("    ", l.Depth(task.Id))
+}
+
+// the history and notes of the task indented by width spaces (plus its nesting) to line up with its description
+func (l *List) detailsBlock(task *Task, width int) string {
+	lines := []string{}
+	if history := task.HistorySummary(); history != "" {
+		lines = append(lines, "("+history+")")
+	}
+	if task.Notes != "" {
+		lines = append(lines, strings.Split(task.Notes, "\n")...)
+	}
+	out := ""
+	for _, line := range lines {
+		out += strings.TrimRight(strings.Repeat(" ", width)+l.indent(task)+line, " ") + "\n"
+	}
+	return out
+}
+
+// number of completed subtasks for tasks that have any
+func (l *List) progressSuffix(task *Task) string {
+	done, total := l.Progress(task.Id)
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d/%d)", done, total)
+}
+
+// label that goes in front of a task's description, or "" for tasks without a priority
+func priorityPrefix(task *Task) string {
+	if task.Priority == PriorityNone {
+		return ""
+	}
+	return fmt.Sprintf("(%s) ", task.Priority)
+}
+
+// explicit tags that are not already visible in the description
+func tagSuffix(task *Task) string {
+	inDescription := map[string]struct{}{}
+	for _, tag := range ParseTags(task.Description) {
+		inDescription[tag] = struct{}{}
+	}
+	out := ""
+	for _, tag := range task.Tags {
+		tag = NormalizeTag(tag)
+		if _, ok := inDescription[tag]; ok || tag == "" {
+			continue
+		}
+		inDescription[tag] = struct{}{}
+		out += " " + tag
+	}
+	return out
+}
+
+// format the dates of a task so they can be appended to its description
+func dateSuffix(task *Task, now time.Time) string {
+	summary := task.DateSummary()
+	if summary == "" {
+		return ""
+	}
+	if task.IsOverdue(now) {
+		return fmt.Sprintf("  (%s) OVERDUE", summary)
+	}
+	return fmt.Sprintf("  (%s)", summary)
+}
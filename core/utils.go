@@ -2,9 +2,17 @@ package core
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 )
 
+// layout used when displaying and exporting dates
+const DateLayout = "2006-01-02"
+
+// layout used when displaying creation, modification and completion times
+const TimestampLayout = "2006-01-02 15:04"
+
 func Success(msg string) {
 	fmt.Println(msg) // this used to print a success message, but that was removed, so now this is effectively a no-op. Too lazy to refactor.
 }
@@ -17,19 +25,62 @@ func ListLists(lists []string, tab string) string {
 	return strings.TrimRight(out, "\n")
 }
 
+// Split the tasks of the list by the completion of their top-level task. Every
+// task is followed by its descendants, so subtasks stay with their parent even
+// if their own completion status differs.
 func SplitByCompletion(list List) (completed, pending []*Task) {
-	for _, i := range list.TaskIds {
-		task, ok := list.Tasks[i]
-		if !ok {
-			continue
+	children := list.childMap()
+	for _, rootId := range children[0] {
+		subtree := appendSubtree(children, rootId, nil)
+		for _, id := range subtree {
+			if list.Tasks[rootId].Done {
+				completed = append(completed, list.Tasks[id])
+			} else {
+				pending = append(pending, list.Tasks[id])
+			}
 		}
-		if task.Done {
-			completed = append(completed, task)
+	}
+	return
+}
+
+// Return a copy of tasks ordered from highest to lowest priority. Tasks with
+// the same priority keep their relative order, so the manual ordering in
+// TaskIds is preserved within each priority level. Subtasks that directly
+// follow their parent (as returned by SplitByCompletion) are moved along with
+// it and keep their order.
+func SortByPriority(tasks []*Task) []*Task {
+	// group every task with the subtasks that follow it
+	groups := [][]*Task{}
+	inGroup := map[int]struct{}{}
+	for _, task := range tasks {
+		if _, ok := inGroup[task.ParentId]; ok && task.ParentId != 0 && len(groups) > 0 {
+			groups[len(groups)-1] = append(groups[len(groups)-1], task)
 		} else {
-			pending = append(pending, task)
+			groups = append(groups, []*Task{task})
+			inGroup = map[int]struct{}{}
 		}
+		inGroup[task.Id] = struct{}{}
 	}
-	return
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i][0].Priority > groups[j][0].Priority
+	})
+	sorted := make([]*Task, 0, len(tasks))
+	for _, group := range groups {
+		sorted = append(sorted, group...)
+	}
+	return sorted
+}
+
+// Return the tasks that carry the given tag, keeping their order.
+func FilterByTag(tasks []*Task, tag string) []*Task {
+	out := []*Task{}
+	for _, task := range tasks {
+		if task.HasTag(tag) {
+			out = append(out, task)
+		}
+	}
+	return out
 }
 
 func RemoveIntFromSlice(s []int, val int) []int {
@@ -40,3 +91,51 @@ func RemoveIntFromSlice(s []int, val int) []int {
 	}
 	return s
 }
+
+// parse a date in either YYYY-MM-DD or RFC3339 format. Plain dates are
+// interpreted in the local time zone.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation(DateLayout, s, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q - expected YYYY-MM-DD or RFC3339", s)
+	}
+	return t, nil
+}
+
+// format a date as YYYY-MM-DD, or "" for the zero time
+func FormatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(DateLayout)
+}
+
+// midnight at the start of the given day in the local time zone
+func StartOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// Parse an age such as 30d, 2w or 12h. Days (d) and weeks (w) are
+// understood in addition to the units of time.ParseDuration.
+func ParseAge(s string) (time.Duration, error) {
+	var n int
+	var unit string
+	if _, err := fmt.Sscanf(s, "%d%s", &n, &unit); err == nil && n >= 0 {
+		switch unit {
+		case "d":
+			return time.Duration(n) * 24 * time.Hour, nil
+		case "w":
+			return time.Duration(n) * 7 * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q - expected e.g. 30d, 2w or 12h", s)
+	}
+	return d, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/jlz22/listly/core"
 	"github.com/stretchr/testify/require"
@@ -37,6 +38,7 @@ func TestInitDB(t *testing.T) {
 	err := db.BoltDB.View(func(tx *bbolt.Tx) error {
 		require.NotNil(t, tx.Bucket([]byte("lists")))
 		require.NotNil(t, tx.Bucket([]byte("currentList")))
+		require.NotNil(t, tx.Bucket([]byte("journal")))
 		return nil
 	})
 	require.NoError(t, err)
@@ -80,6 +82,320 @@ func TestSaveListAndGetList(t *testing.T) {
 	}
 }
 
+func TestSaveListAndGetList_Dates(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("dates")
+	due := time.Date(2025, 6, 20, 0, 0, 0, 0, time.Local)
+	scheduled := time.Date(2025, 6, 18, 9, 30, 0, 0, time.Local)
+
+	withDates, err := list.NewTask("with dates", false)
+	require.NoError(t, err)
+	withDates.Due = due
+	withDates.Scheduled = scheduled
+	require.NoError(t, list.AddTask(withDates))
+	withoutDates, err := list.AddNewTask("without dates", false)
+	require.NoError(t, err)
+
+	require.NoError(t, db.SaveList(list))
+
+	got, err := db.GetList("dates")
+	require.NoError(t, err)
+	require.True(t, got.Tasks[withDates.Id].Due.Equal(due))
+	require.True(t, got.Tasks[withDates.Id].Scheduled.Equal(scheduled))
+	require.True(t, got.Tasks[withoutDates].Due.IsZero())
+	require.True(t, got.Tasks[withoutDates].Scheduled.IsZero())
+
+	// clearing a date should remove it from the database
+	got.Tasks[withDates.Id].Due = time.Time{}
+	require.NoError(t, db.SaveList(got))
+	got, err = db.GetList("dates")
+	require.NoError(t, err)
+	require.True(t, got.Tasks[withDates.Id].Due.IsZero())
+}
+
+func TestSaveListAndGetList_Priority(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("priorities")
+	id, err := list.AddNewTask("urgent task", false)
+	require.NoError(t, err)
+	require.NoError(t, list.SetPriority(id, core.PriorityUrgent))
+	plainId, err := list.AddNewTask("plain task", false)
+	require.NoError(t, err)
+
+	require.NoError(t, db.SaveList(list))
+
+	got, err := db.GetList("priorities")
+	require.NoError(t, err)
+	require.Equal(t, core.PriorityUrgent, got.Tasks[id].Priority)
+	require.Equal(t, core.PriorityNone, got.Tasks[plainId].Priority)
+}
+
+func TestGetTagCounts(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list1 := core.NewList("list1")
+	list1.AddNewTask("api #backend", false)
+	task, err := list1.NewTask("review @alice", true)
+	require.NoError(t, err)
+	task.Tags = []string{"backend"}
+	require.NoError(t, list1.AddTask(task))
+	require.NoError(t, db.SaveList(list1))
+
+	list2 := core.NewList("list2")
+	list2.AddNewTask("db #backend #ops", false)
+	require.NoError(t, db.SaveList(list2))
+
+	counts, err := db.GetTagCounts()
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"#backend": 3, "@alice": 1, "#ops": 1}, counts)
+
+	got, err := db.GetList("list1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"backend"}, got.Tasks[task.Id].Tags)
+}
+
+func TestSaveListAndGetList_Notes(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("notes")
+	id, err := list.AddNewTask("task", false)
+	require.NoError(t, err)
+	require.NoError(t, list.SetNotes(id, "line one\nline two"))
+	require.NoError(t, db.SaveList(list))
+
+	got, err := db.GetList("notes")
+	require.NoError(t, err)
+	require.Equal(t, "line one\nline two", got.Tasks[id].Notes)
+
+	// clearing the notes should remove them from the database
+	require.NoError(t, got.SetNotes(id, ""))
+	require.NoError(t, db.SaveList(got))
+	got, err = db.GetList("notes")
+	require.NoError(t, err)
+	require.Empty(t, got.Tasks[id].Notes)
+}
+
+func TestSaveListAndGetList_UUID(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("imported")
+	id, err := list.AddNewTask("task", false)
+	require.NoError(t, err)
+	list.Tasks[id].UUID = "9f2f1c5e-3c1b-4d8e-8a4e-5d1c2b3a4f5e"
+	require.NoError(t, db.SaveList(list))
+
+	got, err := db.GetList("imported")
+	require.NoError(t, err)
+	require.Equal(t, "9f2f1c5e-3c1b-4d8e-8a4e-5d1c2b3a4f5e", got.Tasks[id].UUID)
+}
+
+func TestSaveListAndGetList_Timestamps(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("timestamps")
+	created := time.Date(2025, 6, 18, 9, 30, 0, 0, time.Local)
+	list.Info.CreatedAt = created
+	task, err := list.NewTask("task", true)
+	require.NoError(t, err)
+	task.CreatedAt = created
+	task.UpdatedAt = created.Add(time.Hour)
+	task.CompletedAt = created.Add(2 * time.Hour)
+	require.NoError(t, list.AddTask(task))
+	require.NoError(t, db.SaveList(list))
+
+	got, err := db.GetList("timestamps")
+	require.NoError(t, err)
+	require.True(t, got.Info.CreatedAt.Equal(created))
+	require.True(t, got.Info.UpdatedAt.Equal(list.Info.UpdatedAt.Truncate(time.Second)))
+	require.True(t, got.Tasks[task.Id].CreatedAt.Equal(task.CreatedAt))
+	require.True(t, got.Tasks[task.Id].UpdatedAt.Equal(task.UpdatedAt))
+	require.True(t, got.Tasks[task.Id].CompletedAt.Equal(task.CompletedAt))
+
+	// renaming a list counts as modifying it
+	require.NoError(t, db.RenameList("timestamps", "renamed"))
+	allInfo, err := db.GetInfo()
+	require.NoError(t, err)
+	require.True(t, allInfo["renamed"].CreatedAt.Equal(created))
+	require.False(t, allInfo["renamed"].UpdatedAt.Before(got.Info.UpdatedAt))
+}
+
+func TestCleanLists_Recurring(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("chores")
+	first, err := list.AddNewTask("first", false)
+	require.NoError(t, err)
+	weekly, err := list.NewTask("weekly review", false)
+	require.NoError(t, err)
+	weekly.Recurrence, _ = core.ParseRecurrence("weekly on fri")
+	require.NoError(t, list.AddTask(weekly))
+	last, err := list.AddNewTask("last", false)
+	require.NoError(t, err)
+	require.NoError(t, list.ToggleCompletion(weekly.Id))
+	require.NoError(t, db.SaveList(list))
+
+	numRemoved, err := db.CleanLists([]string{"chores"}, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, numRemoved)
+
+	got, err := db.GetList("chores")
+	require.NoError(t, err)
+	next := list.Tasks[weekly.Id].NextId
+	require.Equal(t, []int{first, next, last}, got.TaskIds)
+	require.Equal(t, "weekly on fri", got.Tasks[next].Recurrence.String())
+	require.Equal(t, time.Friday, got.Tasks[next].Due.Weekday())
+	require.Equal(t, 3, got.Info.NumPending)
+}
+
+func TestSaveList_RemovedTasks(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("removed")
+	keep, err := list.AddNewTask("keep", false)
+	require.NoError(t, err)
+	drop, err := list.AddNewTask("drop", false)
+	require.NoError(t, err)
+	require.NoError(t, db.SaveList(list))
+
+	require.NoError(t, list.RemoveTask(drop))
+	require.NoError(t, db.SaveList(list))
+
+	got, err := db.GetList("removed")
+	require.NoError(t, err)
+	require.Equal(t, []int{keep}, got.TaskIds)
+	// the removed task should be gone from the database, not just from taskIds
+	numBuckets := 0
+	err = db.BoltDB.View(func(tx *bbolt.Tx) error {
+		tasks := tx.Bucket([]byte("lists")).Bucket([]byte("removed")).Bucket([]byte("data")).Bucket([]byte("tasks"))
+		return tasks.ForEach(func(k, v []byte) error {
+			numBuckets++
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, numBuckets)
+}
+
+func TestGetList_MigratesLongTaskIds(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	// simulate a list saved before task ids were short
+	list := core.NewList("legacy")
+	parent, err := list.NewTask("parent", false)
+	require.NoError(t, err)
+	parent.Id = 8674665223082153551
+	require.NoError(t, list.AddTask(parent))
+	child, err := list.NewTask("child", false)
+	require.NoError(t, err)
+	child.Id = 6129484611666145821
+	child.ParentId = parent.Id
+	require.NoError(t, list.AddTask(child))
+	require.NoError(t, db.SaveList(list))
+	err = db.BoltDB.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte("lists")).Bucket([]byte("legacy")).Bucket([]byte("info")).Delete([]byte("nextId"))
+	})
+	require.NoError(t, err)
+
+	got, err := db.GetList("legacy")
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, got.TaskIds)
+	require.Equal(t, 1, got.Tasks[2].ParentId)
+	require.Equal(t, 3, got.Info.NextId)
+
+	// the new ids are kept once the list is saved
+	id, err := got.AddNewTask("new", false)
+	require.NoError(t, err)
+	require.Equal(t, 3, id)
+	require.NoError(t, db.SaveList(got))
+	got, err = db.GetList("legacy")
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, got.TaskIds)
+	require.Equal(t, "child", got.Tasks[2].Description)
+}
+
+func TestInitDB_AssignsMissingUUIDs(t *testing.T) {
+	tmpDir := t.TempDir()
+	db, err := core.InitDB(tmpDir)
+	require.NoError(t, err)
+	list := core.NewList("legacy")
+	id, err := list.AddNewTask("old task", false)
+	require.NoError(t, err)
+	require.NoError(t, db.SaveList(list))
+	err = db.BoltDB.Update(func(tx *bbolt.Tx) error {
+		tasks := tx.Bucket([]byte("lists")).Bucket([]byte("legacy")).Bucket([]byte("data")).Bucket([]byte("tasks"))
+		return tasks.ForEach(func(k, v []byte) error {
+			return tasks.Bucket(k).Delete([]byte("uuid"))
+		})
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.BoltDB.Close())
+
+	// the task gets a UUID when the database is opened, and keeps it
+	uuids := []string{}
+	for range 2 {
+		db, err = core.InitDB(tmpDir)
+		require.NoError(t, err)
+		got, err := db.GetList("legacy")
+		require.NoError(t, err)
+		require.NotEmpty(t, got.Tasks[id].UUID)
+		uuids = append(uuids, got.Tasks[id].UUID)
+		require.NoError(t, db.BoltDB.Close())
+	}
+	require.Equal(t, uuids[0], uuids[1])
+
+	db, err = core.InitDB(tmpDir)
+	require.NoError(t, err)
+	defer db.BoltDB.Close()
+	entries, err := db.GetJournal("legacy")
+	require.NoError(t, err)
+	require.Len(t, entries, 1) // only the initial save
+}
+
+func TestSaveListAndGetList_Subtasks(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("nested")
+	parent, err := list.AddNewTask("parent", false)
+	require.NoError(t, err)
+	child, err := list.AddNewTask("child", false)
+	require.NoError(t, err)
+	require.NoError(t, list.MoveUnder(child, parent))
+	require.NoError(t, db.SaveList(list))
+
+	got, err := db.GetList("nested")
+	require.NoError(t, err)
+	require.Equal(t, parent, got.Tasks[child].ParentId)
+	require.Equal(t, 0, got.Tasks[parent].ParentId)
+
+	// cleaning a completed parent keeps its pending subtasks as top-level tasks
+	require.NoError(t, got.ToggleCompletion(child))
+	require.True(t, got.Tasks[parent].Done)
+	grandchild, err := got.AddNewTask("grandchild", false)
+	require.NoError(t, err)
+	got.Tasks[grandchild].ParentId = child
+	require.NoError(t, db.SaveList(got))
+
+	numRemoved, err := db.CleanLists([]string{"nested"}, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, numRemoved)
+	got, err = db.GetList("nested")
+	require.NoError(t, err)
+	require.Equal(t, []int{grandchild}, got.TaskIds)
+	require.Equal(t, 0, got.Tasks[grandchild].ParentId)
+}
+
 func TestRenameList(t *testing.T) {
 	db, cleanup := setupTempDB(t)
 	defer cleanup()
@@ -124,6 +440,304 @@ func TestDeleteList(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestDeleteList_Unreadable(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	// a list with data but no info cannot be read, so it must not be deleted for good
+	err := db.BoltDB.Update(func(tx *bbolt.Tx) error {
+		list, err := tx.Bucket([]byte("lists")).CreateBucket([]byte("broken"))
+		if err != nil {
+			return err
+		}
+		data, err := list.CreateBucket([]byte("data"))
+		if err != nil {
+			return err
+		}
+		return data.Put([]byte("1"), []byte("task"))
+	})
+	require.NoError(t, err)
+
+	require.Error(t, db.DeleteLists([]string{"broken"}))
+	err = db.BoltDB.View(func(tx *bbolt.Tx) error {
+		require.NotNil(t, tx.Bucket([]byte("lists")).Bucket([]byte("broken")))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestJournal(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("journal")
+	require.NoError(t, db.SaveList(list))
+	first, _ := list.AddNewTask("first", false)
+	_, _ = list.AddNewTask("second", false)
+	require.NoError(t, db.SaveList(list))
+	require.NoError(t, db.SaveList(list)) // nothing changed, so nothing is recorded
+	require.NoError(t, list.ToggleCompletion(first))
+	require.NoError(t, db.SaveList(list))
+	_, err := db.CleanLists([]string{"journal"}, false)
+	require.NoError(t, err)
+	require.NoError(t, db.RenameList("journal", "renamed"))
+	require.NoError(t, db.DeleteLists([]string{"renamed"}))
+
+	entries, err := db.GetJournal("")
+	require.NoError(t, err)
+	ops := []string{}
+	summaries := []string{}
+	for _, entry := range entries {
+		ops = append(ops, entry.Operation)
+		summaries = append(summaries, entry.Summary())
+	}
+	require.Equal(t, []string{core.OpDelete, core.OpRename, core.OpClean, core.OpSave, core.OpSave, core.OpSave}, ops)
+	require.Equal(t, []string{
+		"deleted with 1 tasks",
+		`renamed from "journal"`,
+		"1 removed",
+		"1 changed",
+		"2 added",
+		"created with 0 tasks",
+	}, summaries)
+
+	// entries are found under the old and the new name of a renamed list
+	entries, err = db.GetJournal("journal")
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+	entries, err = db.GetJournal("renamed")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
+
+func TestJournal_StoresChangedTasks(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("big")
+	for i := range 50 {
+		_, _ = list.AddNewTask(fmt.Sprintf("task %d", i), false)
+	}
+	require.NoError(t, db.SaveList(list))
+	require.NoError(t, list.EditTaskDescription(list.TaskIds[10], "edited"))
+	require.NoError(t, db.SaveList(list))
+
+	entries, err := db.GetJournal("big")
+	require.NoError(t, err)
+	require.Equal(t, "1 changed", entries[0].Summary())
+	after := entries[0].After
+	require.Equal(t, list.TaskIds, after.TaskIds)
+	require.Len(t, after.Tasks, 50)
+	require.Equal(t, "edited", after.Tasks[list.TaskIds[10]].Description)
+	require.Equal(t, "task 11", after.Tasks[list.TaskIds[11]].Description)
+	require.Equal(t, 50, after.Info.NumPending)
+
+	// only the edited task is stored for the state after the change
+	err = db.BoltDB.View(func(tx *bbolt.Tx) error {
+		journal := tx.Bucket([]byte("journal"))
+		k, _ := journal.Cursor().Last()
+		entry := journal.Bucket(k)
+		stored := 0
+		entry.Bucket([]byte("after")).Bucket([]byte("data")).Bucket([]byte("tasks")).ForEach(func(k, v []byte) error {
+			stored++
+			return nil
+		})
+		require.Equal(t, 1, stored)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestRestoreJournalEntry(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("restore")
+	id, _ := list.AddNewTask("done already", true)
+	_, _ = list.AddNewTask("pending", false)
+	require.NoError(t, db.SaveList(list))
+	_, err := db.CleanLists([]string{"restore"}, false)
+	require.NoError(t, err)
+	require.NoError(t, db.DeleteLists([]string{"restore"}))
+
+	entries, err := db.GetJournal("restore")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	deleted, cleaned, created := entries[0], entries[1], entries[2]
+
+	// the entry that created the list has nothing to go back to
+	_, err = db.RestoreJournalEntry(created.Id)
+	require.Error(t, err)
+
+	// restoring the deletion brings back the cleaned list
+	_, err = db.RestoreJournalEntry(deleted.Id)
+	require.NoError(t, err)
+	got, err := db.GetList("restore")
+	require.NoError(t, err)
+	require.Len(t, got.Tasks, 1)
+
+	// restoring the clean brings back the completed task
+	_, err = db.RestoreJournalEntry(cleaned.Id)
+	require.NoError(t, err)
+	got, err = db.GetList("restore")
+	require.NoError(t, err)
+	require.Len(t, got.Tasks, 2)
+	require.True(t, got.Tasks[id].Done)
+
+	entries, err = db.GetJournal("restore")
+	require.NoError(t, err)
+	require.Equal(t, core.OpRestore, entries[0].Operation)
+	require.Equal(t, "1 added", entries[0].Summary())
+}
+
+func TestRestoreJournalEntry_Rename(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	require.NoError(t, db.SaveList(core.NewList("old")))
+	require.NoError(t, db.RenameList("old", "new"))
+	entries, err := db.GetJournal("new")
+	require.NoError(t, err)
+
+	_, err = db.RestoreJournalEntry(entries[0].Id)
+	require.NoError(t, err)
+	exists, err := db.ListExists("old")
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = db.ListExists("new")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestTrash(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("trash")
+	parent, _ := list.AddNewTask("parent", false)
+	child, _ := list.AddNewTask("child", false)
+	_, _ = list.AddNewTask("pending", false)
+	require.NoError(t, list.SetParent(child, parent))
+	require.NoError(t, list.ToggleCompletion(parent))
+	require.NoError(t, db.SaveList(list))
+
+	removed, err := db.CleanLists([]string{"trash"}, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, removed)
+	require.NoError(t, db.DeleteLists([]string{"trash"}))
+
+	items, err := db.GetTrash()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, core.TrashedList, items[0].Kind)
+	require.Equal(t, "list with 1 tasks", items[0].Summary())
+	require.Equal(t, core.TrashedTasks, items[1].Kind)
+	require.Equal(t, "2 cleaned tasks", items[1].Summary())
+
+	// cleaned tasks can only go back into a list that exists
+	_, err = db.RestoreTrashItem(items[1].Id)
+	require.Error(t, err)
+
+	_, err = db.RestoreTrashItem(items[0].Id)
+	require.NoError(t, err)
+	_, err = db.RestoreTrashItem(items[1].Id)
+	require.NoError(t, err)
+
+	got, err := db.GetList("trash")
+	require.NoError(t, err)
+	require.Len(t, got.Tasks, 3)
+	require.True(t, got.Tasks[parent].Done)
+	require.Equal(t, parent, got.Tasks[child].ParentId)
+
+	items, err = db.GetTrash()
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
+func TestRestoreTrashItem_NameTaken(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	require.NoError(t, db.SaveList(core.NewList("taken")))
+	require.NoError(t, db.DeleteLists([]string{"taken"}))
+	require.NoError(t, db.SaveList(core.NewList("taken")))
+
+	items, err := db.GetTrash()
+	require.NoError(t, err)
+	_, err = db.RestoreTrashItem(items[0].Id)
+	require.Error(t, err)
+}
+
+func TestEmptyTrash(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	require.NoError(t, db.SaveList(core.NewList("a")))
+	require.NoError(t, db.SaveList(core.NewList("b")))
+	require.NoError(t, db.DeleteAllLists())
+
+	numDeleted, err := db.EmptyTrash(time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 0, numDeleted)
+
+	numDeleted, err = db.EmptyTrash(0)
+	require.NoError(t, err)
+	require.Equal(t, 2, numDeleted)
+	items, err := db.GetTrash()
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
+func TestCleanLists_Archive(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	list := core.NewList("archived")
+	first, _ := list.AddNewTask("first", false)
+	second, _ := list.AddNewTask("second", false)
+	_, _ = list.AddNewTask("pending", false)
+	require.NoError(t, list.ToggleCompletion(first))
+	require.NoError(t, list.ToggleCompletion(second))
+	list.Tasks[first].CompletedAt = time.Date(2025, 1, 10, 9, 0, 0, 0, time.Local)
+	list.Tasks[second].CompletedAt = time.Date(2025, 2, 10, 9, 0, 0, 0, time.Local)
+	require.NoError(t, db.SaveList(list))
+
+	numRemoved, err := db.CleanLists([]string{"archived"}, true)
+	require.NoError(t, err)
+	require.Equal(t, 2, numRemoved)
+
+	got, err := db.GetList("archived")
+	require.NoError(t, err)
+	require.Len(t, got.Tasks, 1)
+	items, err := db.GetTrash()
+	require.NoError(t, err)
+	require.Empty(t, items)
+
+	archived, err := db.GetArchive("archived", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, archived, 2)
+	require.Equal(t, "second", archived[0].Description)
+	require.Equal(t, "first", archived[1].Description)
+	require.True(t, archived[0].Done)
+	require.False(t, archived[0].ArchivedAt.IsZero())
+
+	archived, err = db.GetArchive("archived", time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	require.Equal(t, "second", archived[0].Description)
+
+	// the archive follows the list through renames and the trash
+	require.NoError(t, db.RenameList("archived", "renamed"))
+	require.NoError(t, db.DeleteLists([]string{"renamed"}))
+	items, err = db.GetTrash()
+	require.NoError(t, err)
+	_, err = db.RestoreTrashItem(items[0].Id)
+	require.NoError(t, err)
+	archived, err = db.GetArchive("renamed", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, archived, 2)
+}
+
 func TestGetInfoEmpty(t *testing.T) {
 	db, cleanup := setupTempDB(t)
 	defer cleanup()
@@ -0,0 +1,140 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jlz22/listly/core"
+	"github.com/stretchr/testify/require"
+)
+
+func filterFixture(t *testing.T, now time.Time) (core.List, map[string]int) {
+	l := core.NewList("filter")
+	ids := map[string]int{}
+	add := func(description string, done bool) *core.Task {
+		id, err := l.AddNewTask(description, done)
+		require.NoError(t, err)
+		ids[description] = id
+		return l.Tasks[id]
+	}
+
+	backend := add("fix login #backend", false)
+	backend.Due = now.AddDate(0, 0, 3)
+	backend.Priority = core.PriorityHigh
+
+	later := add("write docs #backend", false)
+	later.Due = now.AddDate(0, 0, 30)
+
+	late := add("pay rent", false)
+	late.Due = now.AddDate(0, 0, -2)
+	late.Notes = "ask about the lease"
+
+	add("buy milk", true)
+	return l, ids
+}
+
+func matchingIds(t *testing.T, l core.List, query string, now time.Time) []int {
+	filter, err := core.ParseFilter(query, now)
+	require.NoError(t, err, query)
+	ids := []int{}
+	for _, task := range core.FilterTasks(l.DisplayOrder(), filter) {
+		ids = append(ids, task.Id)
+	}
+	return ids
+}
+
+func TestParseFilter(t *testing.T) {
+	now := time.Date(2025, 6, 20, 12, 0, 0, 0, time.Local)
+	l, ids := filterFixture(t, now)
+	login, docs, rent, milk := ids["fix login #backend"], ids["write docs #backend"], ids["pay rent"], ids["buy milk"]
+
+	cases := map[string][]int{
+		"pending and tag:backend and due<7d": {login},
+		"pending tag:backend":                {login, docs},
+		"done":                               {milk},
+		"overdue":                            {rent},
+		"tag:backend or done":                {login, docs, milk},
+		"not (tag:backend or done)":          {rent},
+		"milk":                               {milk},
+		`"fix login"`:                        {login},
+		`desc:"PAY RENT"`:                    {rent},
+		"desc=buy":                           {},
+		"notes:lease":                        {rent},
+		"priority>=high":                     {login},
+		"priority:none":                      {docs, rent, milk},
+		"due:none":                           {milk},
+		"due>=today":                         {login, docs},
+		"due=2025-06-23":                     {login},
+		"due<=-2d":                           {rent},
+		"has:notes":                          {rent},
+		"created>2025-01-01 and not has:due": {milk},
+		"id!=1 and id<=3":                    {docs, rent},
+		"tag!=backend AND pending":           {rent},
+	}
+	for query, want := range cases {
+		require.Equal(t, want, matchingIds(t, l, query, now), query)
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	now := time.Now()
+	cases := map[string]string{
+		"":                   "the filter is empty",
+		"pending and":        "column 12: expected a condition",
+		"or done":            "column 1: expected a condition before \"or\"",
+		"(pending":           "column 1: missing \")\"",
+		"pending)":           "column 8: unexpected \")\"",
+		"colour:red":         "column 1: unknown field \"colour\"",
+		"due<soon":           "column 5: invalid date \"soon\"",
+		"priority>=huge":     "column 11: invalid priority",
+		"tag<backend":        "column 4: operator < cannot be used with tag",
+		"due<":               "column 5: missing value",
+		`desc:"unterminated`: "column 1: missing closing quote",
+		"has:colour":         "column 5: unknown field \"colour\"",
+		"pending and <3":     "column 13: invalid condition",
+	}
+	for query, want := range cases {
+		_, err := core.ParseFilter(query, now)
+		require.Error(t, err, query)
+		require.Contains(t, err.Error(), want, query)
+	}
+}
+
+func TestListFiltered(t *testing.T) {
+	l := core.NewList("filtered")
+	parent, _ := l.AddNewTask("project #work", false)
+	middle, _ := l.AddNewTask("phase one", false)
+	leaf, _ := l.AddNewTask("ship it #work", false)
+	require.NoError(t, l.SetParent(middle, parent))
+	require.NoError(t, l.SetParent(leaf, middle))
+
+	filter, err := core.ParseFilter("tag:work", time.Now())
+	require.NoError(t, err)
+	filtered := l.Filtered(filter)
+	require.Equal(t, []int{parent, leaf}, filtered.TaskIds)
+	require.Equal(t, parent, filtered.Tasks[leaf].ParentId)
+	require.Equal(t, 2, filtered.Info.NumTasks)
+	require.Equal(t, 2, filtered.Info.NumPending)
+	require.Equal(t, 0, filtered.Info.NumDone)
+
+	// the original list is left alone
+	require.Len(t, l.Tasks, 3)
+	require.Equal(t, 3, l.Info.NumTasks)
+	require.Equal(t, middle, l.Tasks[leaf].ParentId)
+}
+
+func TestRender_Filter(t *testing.T) {
+	l := core.NewList("render")
+	_, _ = l.AddNewTask("keep me", false)
+	_, _ = l.AddNewTask("hide me", false)
+
+	filter, err := core.ParseFilter("keep", time.Now())
+	require.NoError(t, err)
+	out := l.Render(core.RenderOptions{Filter: filter})
+	require.Contains(t, out, "keep me")
+	require.NotContains(t, out, "hide me")
+
+	filter, err = core.ParseFilter("nothing", time.Now())
+	require.NoError(t, err)
+	require.Contains(t, l.Render(core.RenderOptions{Filter: filter}), `No tasks matching "nothing" found`)
+}
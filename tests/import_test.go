@@ -0,0 +1,180 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jlz22/listly/core"
+	"github.com/stretchr/testify/require"
+)
+
+// a list with the given tasks, where tasks starting with "- " are subtasks of the task before them
+func newImportList(t *testing.T, name string, descriptions ...string) core.List {
+	list := core.NewList(name)
+	parentId := 0
+	for _, description := range descriptions {
+		if sub, ok := strings.CutPrefix(description, "- "); ok {
+			task, err := list.NewTask(sub, false)
+			require.NoError(t, err)
+			task.ParentId = parentId
+			require.NoError(t, list.AddTask(task))
+			continue
+		}
+		id, err := list.AddNewTask(description, false)
+		require.NoError(t, err)
+		parentId = id
+	}
+	return list
+}
+
+// the descriptions of the tasks of the list in order
+func listDescriptions(list core.List) []string {
+	tasks := []*core.Task{}
+	for _, id := range list.TaskIds {
+		tasks = append(tasks, list.Tasks[id])
+	}
+	return descriptions(tasks)
+}
+
+func TestParseConflictStrategy(t *testing.T) {
+	strategy, err := core.ParseConflictStrategy("Merge")
+	require.NoError(t, err)
+	require.Equal(t, core.ConflictMerge, strategy)
+
+	_, err = core.ParseConflictStrategy("overwrite")
+	require.Error(t, err)
+}
+
+func TestImportLists_Fail(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+	require.NoError(t, db.SaveList(newImportList(t, "work", "existing")))
+
+	// nothing is imported if any list conflicts
+	lists := []core.List{newImportList(t, "home", "dishes"), newImportList(t, "work", "imported")}
+	_, err := db.ImportLists(lists, core.ImportOptions{OnConflict: core.ConflictFail})
+	require.Error(t, err)
+
+	exists, err := db.ListExists("home")
+	require.NoError(t, err)
+	require.False(t, exists)
+	work, err := db.GetList("work")
+	require.NoError(t, err)
+	require.Equal(t, []string{"existing"}, listDescriptions(work))
+}
+
+func TestImportLists_SkipReplaceRename(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+	for _, name := range []string{"skip", "replace", "rename", "rename-2"} {
+		require.NoError(t, db.SaveList(newImportList(t, name, "existing")))
+	}
+
+	imports := []struct {
+		list     core.List
+		strategy core.ConflictStrategy
+		summary  string
+	}{
+		{newImportList(t, "new", "a"), core.ConflictFail, "new: created, 1 tasks added"},
+		{newImportList(t, "skip", "a"), core.ConflictSkip, "skip: skipped"},
+		{newImportList(t, "replace", "a", "b"), core.ConflictReplace, "replace: replaced, 2 tasks added"},
+		{newImportList(t, "rename", "a"), core.ConflictRename, `rename: renamed to "rename-3", 1 tasks added`},
+	}
+	for _, imp := range imports {
+		results, err := db.ImportLists([]core.List{imp.list}, core.ImportOptions{OnConflict: imp.strategy})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, imp.summary, results[0].Summary())
+	}
+
+	skipped, err := db.GetList("skip")
+	require.NoError(t, err)
+	require.Equal(t, []string{"existing"}, listDescriptions(skipped))
+
+	replaced, err := db.GetList("replace")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, listDescriptions(replaced))
+	trash, err := db.GetTrash()
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+
+	renamed, err := db.GetList("rename-3")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, listDescriptions(renamed))
+	original, err := db.GetList("rename")
+	require.NoError(t, err)
+	require.Equal(t, []string{"existing"}, listDescriptions(original))
+}
+
+func TestImportLists_Merge(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+	require.NoError(t, db.SaveList(newImportList(t, "work", "review", "- tests", "deploy")))
+
+	imported := newImportList(t, "work", "review", "- tests", "- docs", "deploy", "release")
+	results, err := db.ImportLists([]core.List{imported}, core.ImportOptions{OnConflict: core.ConflictMerge})
+	require.NoError(t, err)
+	require.Equal(t, "work: merged, 2 tasks added, 3 already present", results[0].Summary())
+
+	work, err := db.GetList("work")
+	require.NoError(t, err)
+	require.Equal(t, []string{"review", "tests", "deploy", "docs", "release"}, listDescriptions(work))
+	docs := work.Tasks[work.TaskIds[3]]
+	require.Equal(t, work.TaskIds[0], docs.ParentId) // nested under the existing task
+	require.Equal(t, 5, work.Info.NumTasks)
+
+	entries, err := db.GetJournal("work")
+	require.NoError(t, err)
+	require.Equal(t, core.OpImport, entries[0].Operation)
+	require.Equal(t, "2 added", entries[0].Summary())
+}
+
+func TestListMerge_ById(t *testing.T) {
+	list := newImportList(t, "work", "review", "deploy")
+	other := core.NewList("work")
+	for _, task := range []struct {
+		id          int
+		description string
+	}{{2, "deploy to production"}, {5, "release"}} {
+		task, err := other.NewTaskWithId(task.id, task.description, false)
+		require.NoError(t, err)
+		require.NoError(t, other.AddTask(task))
+	}
+
+	added, skipped, err := list.Merge(other, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+	require.Equal(t, 1, skipped)
+	require.Equal(t, "deploy", list.Tasks[2].Description) // the existing task is kept
+	require.Equal(t, "release", list.Tasks[5].Description)
+	require.Equal(t, 6, list.Info.NextId)
+}
+
+func TestListMerge_ByUUID(t *testing.T) {
+	list := newImportList(t, "work", "review", "deploy")
+	other := list.Clone()
+	other.Tasks[other.TaskIds[1]].Description = "deploy to production"
+	id, err := other.AddNewTask("release", false)
+	require.NoError(t, err)
+
+	// the renamed task is the same task, so it is not added again
+	added, skipped, err := list.Merge(other, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+	require.Equal(t, 2, skipped)
+	require.Equal(t, []string{"review", "deploy", "release"}, listDescriptions(list))
+	require.Equal(t, other.Tasks[id].UUID, list.Tasks[list.TaskIds[2]].UUID)
+}
+
+func TestImportLists_Current(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+	require.NoError(t, db.SaveList(newImportList(t, "work")))
+
+	lists := []core.List{newImportList(t, "work", "a")}
+	_, err := db.ImportLists(lists, core.ImportOptions{OnConflict: core.ConflictRename, Current: "work"})
+	require.NoError(t, err)
+	current, err := db.GetCurrentListName()
+	require.NoError(t, err)
+	require.Equal(t, "work-2", current) // follows the renamed list
+}
@@ -0,0 +1,49 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/jlz22/listly/tui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadKmap_ToyKmap(t *testing.T) {
+	kmap, err := tui.LoadKmap("../assets/toy_kmap.yaml")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"n"}, kmap.Normal.QuitWithWarning.Keys())
+	require.Equal(t, []string{"q"}, kmap.Normal.NewTask.Keys())
+	require.Equal(t, []string{"ctrl+n"}, kmap.Normal.SearchNext.Keys())
+	require.Equal(t, []string{"ctrl+p"}, kmap.Normal.SearchPrev.Keys())
+}
+
+func TestLoadKmap_OlderDefaults(t *testing.T) {
+	// a key-map written before search existed may use the keys search now has by default
+	pth := filepath.Join(t.TempDir(), "kmap.yaml")
+	require.NoError(t, os.WriteFile(pth, []byte("Normal:\n  NewTask: ctrl+n\n"), 0644))
+
+	kmap, err := tui.LoadKmap(pth)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ctrl+n"}, kmap.Normal.NewTask.Keys())
+	require.False(t, kmap.Normal.SearchNext.Enabled()) // the default is left unbound
+	require.True(t, kmap.Normal.SearchPrev.Enabled())
+	helpView := help.New().FullHelpView(kmap.Normal.FullHelp())
+	require.NotContains(t, helpView, "next match")
+	require.Contains(t, helpView, "previous match")
+}
+
+func TestDefaultKeyMap(t *testing.T) {
+	require.Equal(t, []string{"n"}, tui.DefaultKeyMap.Normal.NewTask.Keys())
+	require.Equal(t, []string{"ctrl+n"}, tui.DefaultKeyMap.Normal.SearchNext.Keys())
+}
+
+func TestLoadKmap_Conflict(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "kmap.yaml")
+	require.NoError(t, os.WriteFile(pth, []byte("Normal:\n  NewTask: w\n  Write: w\n"), 0644))
+
+	_, err := tui.LoadKmap(pth)
+	require.Error(t, err)
+}
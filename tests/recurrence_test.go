@@ -0,0 +1,110 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jlz22/listly/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	cases := map[string]string{
+		"":                     "",
+		"daily":                "daily",
+		"Every day":            "daily",
+		"every 3 days":         "every 3 days",
+		"weekly":               "weekly",
+		"every 2 weeks":        "every 2 weeks",
+		"weekly on thu,Monday": "weekly on mon,thu",
+		"every 2 weeks on fri": "every 2 weeks on fri",
+		"monthly":              "monthly",
+		"every 6 months":       "every 6 months",
+	}
+	for in, want := range cases {
+		r, err := core.ParseRecurrence(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, r.String(), in)
+	}
+
+	for _, in := range []string{"yearly", "every 0 days", "every x days", "monthly on mon", "weekly on funday"} {
+		_, err := core.ParseRecurrence(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	mustParse := func(s string) core.Recurrence {
+		r, err := core.ParseRecurrence(s)
+		require.NoError(t, err)
+		return r
+	}
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	}
+	thursday := date(2025, 6, 19)
+
+	require.Equal(t, date(2025, 6, 20), mustParse("daily").Next(thursday))
+	require.Equal(t, date(2025, 6, 22), mustParse("every 3 days").Next(thursday))
+	require.Equal(t, date(2025, 6, 26), mustParse("weekly").Next(thursday))
+	require.Equal(t, date(2025, 6, 20), mustParse("weekly on mon,fri").Next(thursday))
+	require.Equal(t, date(2025, 6, 23), mustParse("weekly on mon,thu").Next(thursday))
+	require.Equal(t, date(2025, 6, 30), mustParse("every 2 weeks on mon,thu").Next(thursday))
+	require.Equal(t, date(2025, 7, 19), mustParse("monthly").Next(thursday))
+	require.Equal(t, date(2025, 2, 28), mustParse("monthly").Next(date(2025, 1, 31)))
+}
+
+func TestToggleCompletion_SpawnsNextOccurrence(t *testing.T) {
+	l := core.NewList("chores")
+	task, err := l.NewTask("dependency audit", false)
+	require.NoError(t, err)
+	task.Due = time.Date(2025, 6, 19, 0, 0, 0, 0, time.Local)
+	task.Recurrence, _ = core.ParseRecurrence("weekly")
+	require.NoError(t, l.AddTask(task))
+	sub, err := l.AddNewTask("update go.mod", false)
+	require.NoError(t, err)
+	require.NoError(t, l.MoveUnder(sub, task.Id))
+
+	require.NoError(t, l.ToggleCompletion(task.Id))
+	next, ok := l.Tasks[l.Tasks[task.Id].NextId]
+	require.True(t, ok)
+	require.False(t, next.Done)
+	require.Equal(t, "dependency audit", next.Description)
+	require.Equal(t, time.Date(2025, 6, 26, 0, 0, 0, 0, time.Local), next.Due)
+	require.Equal(t, "weekly", next.Recurrence.String())
+	require.Len(t, l.ChildIds(next.Id), 1)
+	require.Equal(t, 4, l.Info.NumTasks)
+	require.Equal(t, 2, l.Info.NumPending)
+
+	// reopening a subtask reopens the task but keeps its next occurrence
+	require.NoError(t, l.ToggleCompletion(sub))
+	require.False(t, l.Tasks[task.Id].Done)
+	require.Equal(t, 4, l.Info.NumTasks)
+
+	// completing it again must not spawn a second occurrence
+	require.NoError(t, l.ToggleCompletion(task.Id))
+	require.Equal(t, 4, l.Info.NumTasks)
+
+	// reopening the task removes the untouched occurrence again
+	require.NoError(t, l.ToggleCompletion(task.Id))
+	require.Equal(t, 2, l.Info.NumTasks)
+	require.Zero(t, l.Tasks[task.Id].NextId)
+	require.Zero(t, l.Tasks[sub].NextId)
+}
+
+func TestRemoveCompleted_KeepsRecurrenceChain(t *testing.T) {
+	l := core.NewList("chores")
+	task, err := l.NewTask("release checklist", true) // completed without spawning, e.g. imported
+	require.NoError(t, err)
+	task.Recurrence, _ = core.ParseRecurrence("every 2 weeks")
+	require.NoError(t, l.AddTask(task))
+	_, err = l.AddNewTask("one-off", true)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, l.RemoveCompleted())
+	require.Len(t, l.TaskIds, 1)
+	next := l.Tasks[l.TaskIds[0]]
+	require.Equal(t, "release checklist", next.Description)
+	require.False(t, next.Done)
+	require.False(t, next.Due.IsZero())
+}
@@ -0,0 +1,77 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/jlz22/listly/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileSearch(t *testing.T) {
+	pattern, err := core.CompileSearch("c++", core.SearchOptions{})
+	require.NoError(t, err)
+	require.True(t, pattern.MatchString("learn C++"))
+
+	pattern, err = core.CompileSearch("Milk", core.SearchOptions{CaseSensitive: true})
+	require.NoError(t, err)
+	require.False(t, pattern.MatchString("buy milk"))
+
+	pattern, err = core.CompileSearch("^buy (milk|eggs)$", core.SearchOptions{Regex: true})
+	require.NoError(t, err)
+	require.True(t, pattern.MatchString("Buy Eggs"))
+	require.False(t, pattern.MatchString("buy bread"))
+
+	_, err = core.CompileSearch("(", core.SearchOptions{Regex: true})
+	require.Error(t, err)
+}
+
+func TestListSearch(t *testing.T) {
+	l := core.NewList("search")
+	milk, _ := l.AddNewTask("buy milk", false)
+	_, _ = l.AddNewTask("walk the dog", false)
+	notes, _ := l.AddNewTask("groceries", false)
+	tagged, _ := l.AddNewTask("errands", true)
+	require.NoError(t, l.SetNotes(notes, "remember the milk"))
+	l.Tasks[tagged].Tags = []string{"#milk"}
+
+	pattern, err := core.CompileSearch("milk", core.SearchOptions{})
+	require.NoError(t, err)
+	matches := l.Search(pattern, core.SearchOptions{})
+	require.Len(t, matches, 3)
+	require.Equal(t, milk, matches[0].Task.Id)
+	require.Equal(t, 1, matches[0].Position)
+	require.Equal(t, notes, matches[1].Task.Id)
+	require.Equal(t, 3, matches[1].Position)
+	require.Equal(t, tagged, matches[2].Task.Id)
+
+	matches = l.Search(pattern, core.SearchOptions{OnlyDone: true})
+	require.Len(t, matches, 1)
+	require.Equal(t, tagged, matches[0].Task.Id)
+
+	matches = l.Search(pattern, core.SearchOptions{OnlyPending: true})
+	require.Len(t, matches, 2)
+}
+
+func TestDBSearch(t *testing.T) {
+	db, cleanup := setupTempDB(t)
+	defer cleanup()
+
+	work := core.NewList("work")
+	_, _ = work.AddNewTask("review PR", false)
+	home := core.NewList("home")
+	_, _ = home.AddNewTask("fix the printer", false)
+	_, _ = home.AddNewTask("water plants", false)
+	require.NoError(t, db.SaveList(work))
+	require.NoError(t, db.SaveList(home))
+	require.NoError(t, db.SaveList(core.NewList("empty")))
+
+	results, err := db.Search("pr", core.SearchOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "home", results[0].List)
+	require.Equal(t, "fix the printer", results[0].Matches[0].Task.Description)
+	require.Equal(t, "work", results[1].List)
+
+	_, err = db.Search("[", core.SearchOptions{Regex: true})
+	require.Error(t, err)
+}
@@ -0,0 +1,122 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/jlz22/listly/core"
+	"github.com/stretchr/testify/require"
+)
+
+// builds the list
+//
+//	a
+//	    b
+//	        c
+//	d
+func newTreeList(t *testing.T) (core.List, map[string]int) {
+	l := core.NewList("tree")
+	ids := map[string]int{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		id, err := l.AddNewTask(name, false)
+		require.NoError(t, err)
+		ids[name] = id
+	}
+	require.NoError(t, l.MoveUnder(ids["b"], ids["a"]))
+	require.NoError(t, l.MoveUnder(ids["c"], ids["b"]))
+	return l, ids
+}
+
+func descriptions(tasks []*core.Task) []string {
+	out := []string{}
+	for _, task := range tasks {
+		out = append(out, task.Description)
+	}
+	return out
+}
+
+func TestTreeStructure(t *testing.T) {
+	l, ids := newTreeList(t)
+
+	require.Equal(t, []int{ids["a"], ids["d"]}, l.ChildIds(0))
+	require.Equal(t, []int{ids["b"]}, l.ChildIds(ids["a"]))
+	require.Equal(t, []int{ids["b"], ids["c"]}, l.DescendantIds(ids["a"]))
+	require.Equal(t, 0, l.Depth(ids["a"]))
+	require.Equal(t, 2, l.Depth(ids["c"]))
+	require.True(t, l.HasChildren(ids["b"]))
+	require.False(t, l.HasChildren(ids["c"]))
+}
+
+func TestMoveUnder_Invalid(t *testing.T) {
+	l, ids := newTreeList(t)
+
+	require.Error(t, l.MoveUnder(ids["a"], ids["a"]))
+	require.Error(t, l.MoveUnder(ids["a"], ids["c"]))
+	require.Error(t, l.MoveUnder(ids["a"], 12345))
+}
+
+func TestMoveUnder_BecomesLastChild(t *testing.T) {
+	l, ids := newTreeList(t)
+
+	require.NoError(t, l.MoveUnder(ids["d"], ids["a"]))
+	require.Equal(t, []int{ids["b"], ids["d"]}, l.ChildIds(ids["a"]))
+
+	_, pending := core.SplitByCompletion(l)
+	require.Equal(t, []string{"a", "b", "c", "d"}, descriptions(pending))
+}
+
+func TestOutdent(t *testing.T) {
+	l, ids := newTreeList(t)
+
+	require.NoError(t, l.Outdent(ids["b"]))
+	require.Equal(t, []int{ids["a"], ids["b"], ids["d"]}, l.ChildIds(0))
+	require.Equal(t, []int{ids["c"]}, l.ChildIds(ids["b"]))
+
+	require.Error(t, l.Outdent(ids["a"]))
+}
+
+func TestToggleCompletion_Subtasks(t *testing.T) {
+	l, ids := newTreeList(t)
+
+	// completing the last subtask completes its ancestors
+	require.NoError(t, l.ToggleCompletion(ids["c"]))
+	require.True(t, l.Tasks[ids["b"]].Done)
+	require.True(t, l.Tasks[ids["a"]].Done)
+	require.Equal(t, 3, l.Info.NumDone)
+	require.Equal(t, 1, l.Info.NumPending)
+
+	// reopening a parent reopens its subtasks
+	require.NoError(t, l.ToggleCompletion(ids["a"]))
+	require.False(t, l.Tasks[ids["b"]].Done)
+	require.False(t, l.Tasks[ids["c"]].Done)
+	require.Equal(t, 0, l.Info.NumDone)
+
+	done, total := l.Progress(ids["a"])
+	require.Equal(t, 0, done)
+	require.Equal(t, 2, total)
+}
+
+func TestSplitByCompletion_KeepsSubtasksWithParent(t *testing.T) {
+	l, ids := newTreeList(t)
+	require.NoError(t, l.ToggleCompletion(ids["d"]))
+	require.NoError(t, l.MoveUnder(ids["d"], ids["b"]))
+
+	completed, pending := core.SplitByCompletion(l)
+	require.Empty(t, completed)
+	require.Equal(t, []string{"a", "b", "c", "d"}, descriptions(pending))
+}
+
+func TestRemoveTask_PromotesSubtasks(t *testing.T) {
+	l, ids := newTreeList(t)
+
+	require.NoError(t, l.RemoveTask(ids["b"]))
+	require.Equal(t, ids["a"], l.Tasks[ids["c"]].ParentId)
+	require.Equal(t, []int{ids["c"]}, l.ChildIds(ids["a"]))
+}
+
+func TestSortByPriority_KeepsSubtasksWithParent(t *testing.T) {
+	l, ids := newTreeList(t)
+	require.NoError(t, l.SetPriority(ids["d"], core.PriorityHigh))
+
+	_, pending := core.SplitByCompletion(l)
+	require.Equal(t, []string{"d", "a", "b", "c"}, descriptions(core.SortByPriority(pending)))
+}
@@ -3,6 +3,7 @@ package core_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/jlz22/listly/core"
 	"github.com/stretchr/testify/require"
@@ -187,6 +188,34 @@ func TestUsedIdsUpdate(t *testing.T) {
 	}
 }
 
+func TestNewTaskWithId(t *testing.T) {
+	l := core.NewList("test")
+
+	task, err := l.NewTaskWithId(7, "restored", true)
+	require.NoError(t, err)
+	require.Equal(t, 7, task.Id)
+	require.False(t, task.CompletedAt.IsZero())
+	require.NoError(t, l.AddTask(task))
+
+	// later tasks continue after the restored id
+	id, err := l.AddNewTask("new", false)
+	require.NoError(t, err)
+	require.Equal(t, 8, id)
+
+	_, err = l.NewTaskWithId(7, "duplicate", false)
+	require.Error(t, err)
+	_, err = l.NewTaskWithId(0, "invalid", false)
+	require.Error(t, err)
+}
+
+func TestNewTask_UUID(t *testing.T) {
+	l := core.NewList("test")
+	first, _ := l.AddNewTask("first", false)
+	second, _ := l.AddNewTask("second", false)
+	require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, l.Tasks[first].UUID)
+	require.NotEqual(t, l.Tasks[first].UUID, l.Tasks[second].UUID)
+}
+
 func TestRemoveTask_NotFound(t *testing.T) {
 	l := core.NewList("test")
 	err := l.RemoveTask(999)
@@ -241,3 +270,248 @@ func TestListInfoTracking(t *testing.T) {
 		t.Errorf("unexpected counts after toggle back: %+v", l.Info)
 	}
 }
+
+func TestIsOverdue(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.Local)
+
+	task := core.Task{Description: "no due date"}
+	require.False(t, task.IsOverdue(now))
+
+	task.Due = time.Date(2025, 6, 14, 0, 0, 0, 0, time.Local)
+	require.True(t, task.IsOverdue(now))
+
+	task.Due = time.Date(2025, 6, 15, 0, 0, 0, 0, time.Local) // due today is not overdue yet
+	require.False(t, task.IsOverdue(now))
+
+	task.Due = time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	task.Done = true
+	require.False(t, task.IsOverdue(now))
+}
+
+func TestDateSummary(t *testing.T) {
+	task := core.Task{Description: "task"}
+	require.Equal(t, "", task.DateSummary())
+
+	task.Due = time.Date(2025, 6, 20, 0, 0, 0, 0, time.Local)
+	require.Equal(t, "due 2025-06-20", task.DateSummary())
+
+	task.Scheduled = time.Date(2025, 6, 18, 0, 0, 0, 0, time.Local)
+	require.Equal(t, "scheduled 2025-06-18, due 2025-06-20", task.DateSummary())
+}
+
+func TestParseDate(t *testing.T) {
+	got, err := core.ParseDate("2025-06-20")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 6, 20, 0, 0, 0, 0, time.Local), got)
+
+	got, err = core.ParseDate("2025-06-20T15:04:05Z")
+	require.NoError(t, err)
+	require.True(t, got.Equal(time.Date(2025, 6, 20, 15, 4, 5, 0, time.UTC)))
+
+	_, err = core.ParseDate("next tuesday")
+	require.Error(t, err)
+}
+
+func TestParseAge(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30d": 30 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+		"90m": 90 * time.Minute,
+	}
+	for input, want := range cases {
+		got, err := core.ParseAge(input)
+		require.NoError(t, err, input)
+		require.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"", "soon", "-3d", "-1h", "3x"} {
+		_, err := core.ParseAge(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	p, err := core.ParsePriority("High")
+	require.NoError(t, err)
+	require.Equal(t, core.PriorityHigh, p)
+
+	p, err = core.ParsePriority("")
+	require.NoError(t, err)
+	require.Equal(t, core.PriorityNone, p)
+
+	_, err = core.ParsePriority("critical")
+	require.Error(t, err)
+}
+
+func TestRaiseAndLowerPriority(t *testing.T) {
+	require.Equal(t, core.PriorityLow, core.PriorityNone.Raise())
+	require.Equal(t, core.PriorityUrgent, core.PriorityUrgent.Raise())
+	require.Equal(t, core.PriorityHigh, core.PriorityUrgent.Lower())
+	require.Equal(t, core.PriorityNone, core.PriorityNone.Lower())
+}
+
+func TestSortByPriority(t *testing.T) {
+	l := core.NewList("test")
+	priorities := []core.Priority{core.PriorityLow, core.PriorityNone, core.PriorityHigh, core.PriorityLow, core.PriorityUrgent}
+	for i, p := range priorities {
+		id, err := l.AddNewTask(fmt.Sprintf("task %d", i+1), false)
+		require.NoError(t, err)
+		require.NoError(t, l.SetPriority(id, p))
+	}
+	originalOrder := append([]int{}, l.TaskIds...)
+
+	_, pending := core.SplitByCompletion(l)
+	sorted := core.SortByPriority(pending)
+	got := []string{}
+	for _, task := range sorted {
+		got = append(got, task.Description)
+	}
+	require.Equal(t, []string{"task 5", "task 3", "task 1", "task 4", "task 2"}, got)
+
+	// sorting must not touch the manual order
+	require.Equal(t, originalOrder, l.TaskIds)
+	require.Equal(t, "task 1", pending[0].Description)
+}
+
+func TestSetPriority_NotFound(t *testing.T) {
+	l := core.NewList("test")
+	err := l.SetPriority(123, core.PriorityHigh)
+	require.Error(t, err)
+}
+
+func TestParseTags(t *testing.T) {
+	require.Equal(t, []string{"#backend", "@alice"}, core.ParseTags("fix login #Backend for @alice"))
+	require.Equal(t, []string{}, core.ParseTags("email me at bob@example.com, issue#4"))
+}
+
+func TestAllTagsAndHasTag(t *testing.T) {
+	task := core.Task{
+		Description: "deploy #backend with @alice",
+		Tags:        []string{"release", "#backend"},
+	}
+	require.Equal(t, []string{"#backend", "#release", "@alice"}, task.AllTags())
+
+	require.True(t, task.HasTag("backend"))
+	require.True(t, task.HasTag("#release"))
+	require.True(t, task.HasTag("alice"))
+	require.True(t, task.HasTag("@alice"))
+	require.False(t, task.HasTag("#alice"))
+	require.False(t, task.HasTag("frontend"))
+}
+
+func TestRenderWithTag(t *testing.T) {
+	l := core.NewList("tags")
+	l.AddNewTask("api #backend", false)
+	l.AddNewTask("css #frontend", false)
+
+	out := l.Render(core.RenderOptions{Tag: "backend"})
+	require.Contains(t, out, "api #backend")
+	require.NotContains(t, out, "css #frontend")
+
+	out = l.Render(core.RenderOptions{Tag: "missing"})
+	require.Contains(t, out, "No tasks tagged")
+}
+
+func TestSetNotesAndRenderVerbose(t *testing.T) {
+	l := core.NewList("notes")
+	id, _ := l.AddNewTask("write docs", false)
+	l.AddNewTask("no notes", false)
+
+	require.NoError(t, l.SetNotes(id, "see https://example.com\nmust cover setup\n\n"))
+	require.Equal(t, "see https://example.com\nmust cover setup", l.Tasks[id].Notes)
+	require.Error(t, l.SetNotes(12345, "missing"))
+
+	require.NotContains(t, l.Render(core.RenderOptions{}), "must cover setup")
+	out := l.Render(core.RenderOptions{Verbose: true})
+	require.Contains(t, out, "\n       see https://example.com\n       must cover setup\n")
+}
+
+func TestTimestamps(t *testing.T) {
+	start := time.Now()
+	l := core.NewList("timestamps")
+	require.False(t, l.Info.CreatedAt.Before(start))
+
+	id, err := l.AddNewTask("task", false)
+	require.NoError(t, err)
+	task := l.Tasks[id]
+	require.False(t, task.CreatedAt.Before(start))
+	require.Equal(t, task.CreatedAt, task.UpdatedAt)
+	require.True(t, task.CompletedAt.IsZero())
+	require.False(t, l.Info.UpdatedAt.Before(task.CreatedAt))
+
+	require.NoError(t, l.EditTaskDescription(id, "edited"))
+	require.False(t, task.UpdatedAt.Before(task.CreatedAt))
+
+	require.NoError(t, l.ToggleCompletion(id))
+	require.False(t, task.CompletedAt.IsZero())
+	require.Equal(t, task.CompletedAt, task.UpdatedAt)
+	require.Contains(t, task.HistorySummary(), "completed ")
+
+	require.NoError(t, l.ToggleCompletion(id))
+	require.True(t, task.CompletedAt.IsZero())
+
+	done, err := l.AddNewTask("done", true)
+	require.NoError(t, err)
+	require.Equal(t, l.Tasks[done].CreatedAt, l.Tasks[done].CompletedAt)
+}
+
+func TestTaskAtPosition(t *testing.T) {
+	l := core.NewList("positions")
+	done, _ := l.AddNewTask("done", true)
+	first, _ := l.AddNewTask("first", false)
+	second, _ := l.AddNewTask("second", false)
+	l.SetPriority(second, core.PriorityHigh)
+
+	for position, want := range map[int]int{1: first, 2: second, 3: done} {
+		task, err := l.TaskAtPosition(position)
+		require.NoError(t, err)
+		require.Equal(t, want, task.Id)
+	}
+	_, err := l.TaskAtPosition(0)
+	require.Error(t, err)
+	_, err = l.TaskAtPosition(4)
+	require.Error(t, err)
+
+	// sorting does not change the printed positions
+	out := l.Render(core.RenderOptions{Numbered: true, ByPriority: true})
+	require.Contains(t, out, "  2 [ ] (high) second\n  1 [ ] first\n  3 [x] done\n")
+}
+
+func TestTaskIdsAreShortAndNotReused(t *testing.T) {
+	l := core.NewList("ids")
+	first, _ := l.AddNewTask("first", false)
+	second, _ := l.AddNewTask("second", false)
+	require.Equal(t, 1, first)
+	require.Equal(t, 2, second)
+
+	require.NoError(t, l.RemoveTask(second))
+	third, _ := l.AddNewTask("third", false)
+	require.Equal(t, 3, third)
+
+	// ids stay the same when tasks are reordered
+	require.NoError(t, l.MoveUnder(first, third))
+	require.Equal(t, "first", l.Tasks[first].Description)
+	require.Contains(t, l.Render(core.RenderOptions{Ids: true}), "  3 [ ] third (0/1)\n  1     [ ] first\n")
+}
+
+func TestClone(t *testing.T) {
+	l := core.NewList("clone")
+	id, _ := l.AddNewTask("water plants", false)
+	l.Tasks[id].Tags = []string{"#home"}
+	recurrence, err := core.ParseRecurrence("weekly on mon")
+	require.NoError(t, err)
+	require.NoError(t, l.SetRecurrence(id, recurrence))
+
+	clone := l.Clone()
+	require.NoError(t, clone.ToggleCompletion(id))
+	clone.Tasks[id].Tags[0] = "#garden"
+	clone.Tasks[id].Recurrence.Weekdays[0] = time.Friday
+	_, _ = clone.AddNewTask("another", false)
+
+	require.False(t, l.Tasks[id].Done)
+	require.Equal(t, []string{"#home"}, l.Tasks[id].Tags)
+	require.Equal(t, []time.Weekday{time.Monday}, l.Tasks[id].Recurrence.Weekdays)
+	require.Len(t, l.TaskIds, 1)
+	require.Len(t, l.Tasks, 1)
+}
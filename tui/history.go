@@ -0,0 +1,60 @@
+package tui
+
+import "github.com/jlz22/listly/core"
+
+// maximum number of changes that can be undone
+const maxHistory = 100
+
+// the state of the list before or after a change
+type snapshot struct {
+	list core.List
+	row  int // cursor row
+}
+
+type history struct {
+	undo []snapshot
+	redo []snapshot
+}
+
+// Remember the current state of the list so that the change about to be made
+// can be undone. Making a new change discards the changes that could be redone.
+func recordChange(m model) model {
+	m.history.undo = append(m.history.undo, snapshot{list: m.data.list.Clone(), row: m.cursor.row})
+	if len(m.history.undo) > maxHistory {
+		m.history.undo = m.history.undo[1:]
+	}
+	m.history.redo = nil
+	return m
+}
+
+// revert the last change
+func undo(m model) model {
+	if len(m.history.undo) == 0 {
+		m.status = "Already at oldest change"
+		return m
+	}
+	last := m.history.undo[len(m.history.undo)-1]
+	m.history.undo = m.history.undo[:len(m.history.undo)-1]
+	m.history.redo = append(m.history.redo, snapshot{list: m.data.list, row: m.cursor.row})
+	return restoreSnapshot(m, last)
+}
+
+// reapply the last change that was undone
+func redo(m model) model {
+	if len(m.history.redo) == 0 {
+		m.status = "Already at newest change"
+		return m
+	}
+	last := m.history.redo[len(m.history.redo)-1]
+	m.history.redo = m.history.redo[:len(m.history.redo)-1]
+	m.history.undo = append(m.history.undo, snapshot{list: m.data.list, row: m.cursor.row})
+	return restoreSnapshot(m, last)
+}
+
+func restoreSnapshot(m model, s snapshot) model {
+	m.data.list = s.list
+	m.editInfo.dirty = true
+	numPending, numDone := countDisplayed(m)
+	m.cursor.row = max(0, min(s.row, numPending+numDone-1))
+	return m
+}
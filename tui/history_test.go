@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/jlz22/listly/core"
+	"github.com/stretchr/testify/require"
+)
+
+func newHistoryModel(t *testing.T) model {
+	m, err := NewModel(core.NewList("test"), DefaultKeyMap)
+	require.NoError(t, err)
+	return m
+}
+
+// record a change and add a task, the way the TUI edits the list
+func addTask(t *testing.T, m model, description string) model {
+	m = recordChange(m)
+	_, err := m.data.list.AddNewTask(description, false)
+	require.NoError(t, err)
+	return m
+}
+
+func taskDescriptions(m model) []string {
+	out := []string{}
+	for _, id := range m.data.list.TaskIds {
+		out = append(out, m.data.list.Tasks[id].Description)
+	}
+	return out
+}
+
+func TestUndoRedo(t *testing.T) {
+	m := newHistoryModel(t)
+	m = addTask(t, m, "a")
+	m = addTask(t, m, "b")
+	require.Len(t, m.history.undo, 2)
+
+	m = undo(m)
+	require.Equal(t, []string{"a"}, taskDescriptions(m))
+	require.True(t, m.editInfo.dirty)
+	m = undo(m)
+	require.Equal(t, []string{}, taskDescriptions(m))
+	m = undo(m)
+	require.Equal(t, "Already at oldest change", m.status)
+	require.Equal(t, []string{}, taskDescriptions(m))
+
+	m = redo(m)
+	m = redo(m)
+	require.Equal(t, []string{"a", "b"}, taskDescriptions(m))
+	m.status = ""
+	m = redo(m)
+	require.Equal(t, "Already at newest change", m.status)
+	require.Len(t, m.history.undo, 2)
+}
+
+func TestUndo_KeepsSnapshotsApart(t *testing.T) {
+	m := newHistoryModel(t)
+	m = addTask(t, m, "a")
+	m = recordChange(m)
+	m.data.list.Tasks[m.data.list.TaskIds[0]].Description = "edited"
+
+	// editing the list after recording the change leaves the snapshot alone
+	m = undo(m)
+	require.Equal(t, []string{"a"}, taskDescriptions(m))
+	m = redo(m)
+	require.Equal(t, []string{"edited"}, taskDescriptions(m))
+}
+
+func TestRecordChange_ClearsRedo(t *testing.T) {
+	m := newHistoryModel(t)
+	m = addTask(t, m, "a")
+	m = addTask(t, m, "b")
+	m = undo(m)
+	require.Len(t, m.history.redo, 1)
+
+	m = addTask(t, m, "c")
+	require.Empty(t, m.history.redo)
+	m = redo(m)
+	require.Equal(t, "Already at newest change", m.status)
+	require.Equal(t, []string{"a", "c"}, taskDescriptions(m))
+}
+
+func TestRecordChange_MaxHistory(t *testing.T) {
+	m := newHistoryModel(t)
+	for range maxHistory + 5 {
+		m = addTask(t, m, "task")
+	}
+	require.Len(t, m.history.undo, maxHistory)
+
+	for range maxHistory {
+		m = undo(m)
+	}
+	require.Len(t, m.data.list.TaskIds, 5) // the oldest changes can no longer be undone
+	m = undo(m)
+	require.Equal(t, "Already at oldest change", m.status)
+}
+
+func TestUndo_CursorStaysInList(t *testing.T) {
+	m := newHistoryModel(t)
+	m = addTask(t, m, "a")
+	m = addTask(t, m, "b")
+	m.cursor.row = 1
+	m = recordChange(m)
+	require.NoError(t, m.data.list.RemoveTask(m.data.list.TaskIds[1]))
+	m.cursor.row = 0
+
+	m = undo(m)
+	require.Equal(t, 1, m.cursor.row) // back on the task that was removed
+	m = redo(m)
+	require.Equal(t, 0, m.cursor.row)
+
+	m.history.undo = []snapshot{{list: core.NewList("test"), row: 3}}
+	m = undo(m)
+	require.Equal(t, 0, m.cursor.row)
+}
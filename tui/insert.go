@@ -32,7 +32,8 @@ func handleInsertInput(msg tea.Msg, m model) (model, tea.Cmd) {
 
 func renderInsertView(m model) string {
 	lines := buildLines(m, false)
-	idx := min(m.editInfo.location, m.data.list.Info.NumPending) + 1
+	numPending, _ := countDisplayed(m)
+	idx := min(m.editInfo.location, numPending) + 1
 
 	if m.editInfo.taskId == -1 {
 		// insert the view between two tasks because we're creating a new task
@@ -55,15 +56,17 @@ func insertToNormal(m model) model {
 	m.editInfo.textInput.Reset()
 	m.mode = "normal"
 	m.editInfo.taskId = -1
+	m.editInfo.parentId = 0
 	return m
 }
 
 func keepChanges(m model) model {
+	m = recordChange(m)
 	idx := m.editInfo.location
 	if m.editInfo.taskId == -1 {
 		var taskIndex int
-		if len(m.data.list.TaskIds) == 0 {
-			taskIndex = 0
+		if numPending, numDone := countDisplayed(m); numPending+numDone == 0 {
+			taskIndex = len(m.data.list.TaskIds)
 		} else {
 			if idx > 0 {
 				taskIndex = getTaskIndex(m, idx-1) + 1
@@ -71,11 +74,12 @@ func keepChanges(m model) model {
 				taskIndex = getTaskIndex(m, idx)
 			}
 		}
-		_, err := m.data.list.InsertNewTask(m.editInfo.textInput.Value(), taskIndex)
+		taskId, err := m.data.list.InsertNewTask(m.editInfo.textInput.Value(), taskIndex)
 		if err != nil {
 			return m
 		}
-		m.cursor.row = idx
+		m.data.list.SetParent(taskId, m.editInfo.parentId)
+		m.cursor.row = getDisplayIdx(m, taskId)
 	} else {
 		m.data.list.EditTaskDescription(m.editInfo.taskId, m.editInfo.textInput.Value())
 	}
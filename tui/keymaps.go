@@ -20,6 +20,8 @@ var DefaultKeyMapConfig = map[string]map[string]string{
 		"ToggleCompletion": " ",
 		"JumpUp":           "{",
 		"JumpDown":         "}",
+		"RaisePriority":    "+",
+		"LowerPriority":    "-",
 	},
 	"Normal": {
 		"QuitWithWarning":  "q",
@@ -33,6 +35,19 @@ var DefaultKeyMapConfig = map[string]map[string]string{
 		"PasteAfter":       "p",
 		"PasteBefore":      "P",
 		"Write":            "w",
+		"SortByPriority":   "s",
+		"FilterTag":        "t",
+		"Indent":           ">",
+		"Outdent":          "<",
+		"ToggleFold":       "z",
+		"EditNotes":        "e",
+		"SetRecurrence":    "r",
+		"Undo":             "u",
+		"Redo":             "ctrl+r",
+		"Filter":           "f",
+		"Search":           "/",
+		"SearchNext":       "ctrl+n",
+		"SearchPrev":       "ctrl+p",
 	},
 	"Insert": {
 		"Discard": "esc",
@@ -67,7 +82,10 @@ var normalCommands = []string{
 	"Up", "UpFive", "Down", "DownFive", "QuitWithWarning", "QuitNoWarning",
 	"NewTask", "NewBefore", "NewAfter", "EditTask", "ClearAndEdit", "DeleteTask",
 	"ToggleCompletion", "EnableVisualMode", "Yank", "PasteAfter", "PasteBefore",
-	"Write", "JumpUp", "JumpDown",
+	"Write", "JumpUp", "JumpDown", "RaisePriority", "LowerPriority", "SortByPriority",
+	"FilterTag", "Indent", "Outdent", "ToggleFold", "EditNotes",
+	"SetRecurrence", "Undo", "Redo",
+	"Filter", "Search", "SearchNext", "SearchPrev",
 }
 
 type NormalKeyMap struct {
@@ -91,6 +109,21 @@ type NormalKeyMap struct {
 	Write            key.Binding
 	JumpUp           key.Binding
 	JumpDown         key.Binding
+	RaisePriority    key.Binding
+	LowerPriority    key.Binding
+	SortByPriority   key.Binding
+	FilterTag        key.Binding
+	Indent           key.Binding
+	Outdent          key.Binding
+	ToggleFold       key.Binding
+	EditNotes        key.Binding
+	SetRecurrence    key.Binding
+	Undo             key.Binding
+	Redo             key.Binding
+	Filter           key.Binding
+	Search           key.Binding
+	SearchNext       key.Binding
+	SearchPrev       key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
@@ -113,6 +146,11 @@ func (k NormalKeyMap) FullHelp() [][]key.Binding {
 		{k.EnableVisualMode, k.PasteAfter, k.PasteBefore}, // fourth column
 		{k.JumpUp, k.JumpDown, k.ToggleCompletion},        // fifth column
 		{k.NewBefore, k.NewAfter},
+		{k.RaisePriority, k.LowerPriority, k.SortByPriority},
+		{k.FilterTag, k.Indent, k.Outdent},
+		{k.ToggleFold, k.EditNotes, k.SetRecurrence},
+		{k.Undo, k.Redo},
+		{k.Filter, k.Search, k.SearchNext, k.SearchPrev},
 	}
 }
 
@@ -129,84 +167,144 @@ func buildNormalKmapFromConfig(config map[string]string) (NormalKeyMap, error) {
 	// Build the NormalKeyMap
 	return NormalKeyMap{
 		Up: key.NewBinding(
-			key.WithKeys(config["Up"]),
+			bindKeys(config["Up"]),
 			key.WithHelp(config["Up"], "up"),
 		),
 		Down: key.NewBinding(
-			key.WithKeys(config["Down"]),
+			bindKeys(config["Down"]),
 			key.WithHelp(config["Down"], "down"),
 		),
 		UpFive: key.NewBinding(
-			key.WithKeys(config["UpFive"]),
+			bindKeys(config["UpFive"]),
 			key.WithHelp(config["UpFive"], "up 5"),
 		),
 		DownFive: key.NewBinding(
-			key.WithKeys(config["DownFive"]),
+			bindKeys(config["DownFive"]),
 			key.WithHelp(config["DownFive"], "down 5"),
 		),
 		QuitWithWarning: key.NewBinding(
-			key.WithKeys(config["QuitWithWarning"]),
+			bindKeys(config["QuitWithWarning"]),
 			key.WithHelp(config["QuitWithWarning"], "quit"),
 		),
 		QuitNoWarning: key.NewBinding(
-			key.WithKeys(config["QuitNoWarning"]),
+			bindKeys(config["QuitNoWarning"]),
 		),
 		NewTask: key.NewBinding(
-			key.WithKeys(config["NewTask"]),
+			bindKeys(config["NewTask"]),
 			key.WithHelp(config["NewTask"], "new task"),
 		),
 		NewBefore: key.NewBinding(
-			key.WithKeys(config["NewBefore"]),
+			bindKeys(config["NewBefore"]),
 			key.WithHelp(config["NewBefore"], "new task before"),
 		),
 		NewAfter: key.NewBinding(
-			key.WithKeys(config["NewAfter"]),
+			bindKeys(config["NewAfter"]),
 			key.WithHelp(config["NewAfter"], "new task after"),
 		),
 		EditTask: key.NewBinding(
-			key.WithKeys(config["EditTask"]),
+			bindKeys(config["EditTask"]),
 			key.WithHelp(config["EditTask"], "edit task"),
 		),
 		ClearAndEdit: key.NewBinding(
-			key.WithKeys(config["ClearAndEdit"]),
+			bindKeys(config["ClearAndEdit"]),
 			key.WithHelp(config["ClearAndEdit"], "clear and edit"),
 		),
 		DeleteTask: key.NewBinding(
-			key.WithKeys(config["DeleteTask"]),
+			bindKeys(config["DeleteTask"]),
 			key.WithHelp(config["DeleteTask"], "cut task"),
 		),
 		ToggleCompletion: key.NewBinding(
-			key.WithKeys(config["ToggleCompletion"]),
+			bindKeys(config["ToggleCompletion"]),
 			key.WithHelp(config["ToggleCompletion"], "mark done/not done"),
 		),
 		EnableVisualMode: key.NewBinding(
-			key.WithKeys(config["EnableVisualMode"]),
+			bindKeys(config["EnableVisualMode"]),
 			key.WithHelp(config["EnableVisualMode"], "visual mode"),
 		),
 		Yank: key.NewBinding(
-			key.WithKeys(config["Yank"]),
+			bindKeys(config["Yank"]),
 			key.WithHelp(config["Yank"], "yank"),
 		),
 		PasteAfter: key.NewBinding(
-			key.WithKeys(config["PasteAfter"]),
+			bindKeys(config["PasteAfter"]),
 			key.WithHelp(config["PasteAfter"], "paste"),
 		),
 		PasteBefore: key.NewBinding(
-			key.WithKeys(config["PasteBefore"]),
+			bindKeys(config["PasteBefore"]),
 			key.WithHelp(config["PasteBefore"], "paste before"),
 		),
 		Write: key.NewBinding(
-			key.WithKeys(config["Write"]),
+			bindKeys(config["Write"]),
 			key.WithHelp(config["Write"], "write"),
 		),
 		JumpUp: key.NewBinding(
-			key.WithKeys(config["JumpUp"]),
+			bindKeys(config["JumpUp"]),
 			key.WithHelp(config["JumpUp"], "jump up"),
 		),
 		JumpDown: key.NewBinding(
-			key.WithKeys(config["JumpDown"]),
+			bindKeys(config["JumpDown"]),
 			key.WithHelp(config["JumpDown"], "jump down"),
 		),
+		RaisePriority: key.NewBinding(
+			bindKeys(config["RaisePriority"]),
+			key.WithHelp(config["RaisePriority"], "raise priority"),
+		),
+		LowerPriority: key.NewBinding(
+			bindKeys(config["LowerPriority"]),
+			key.WithHelp(config["LowerPriority"], "lower priority"),
+		),
+		SortByPriority: key.NewBinding(
+			bindKeys(config["SortByPriority"]),
+			key.WithHelp(config["SortByPriority"], "sort by priority"),
+		),
+		FilterTag: key.NewBinding(
+			bindKeys(config["FilterTag"]),
+			key.WithHelp(config["FilterTag"], "filter by tag"),
+		),
+		Indent: key.NewBinding(
+			bindKeys(config["Indent"]),
+			key.WithHelp(config["Indent"], "make subtask"),
+		),
+		Outdent: key.NewBinding(
+			bindKeys(config["Outdent"]),
+			key.WithHelp(config["Outdent"], "move out of parent"),
+		),
+		ToggleFold: key.NewBinding(
+			bindKeys(config["ToggleFold"]),
+			key.WithHelp(config["ToggleFold"], "fold/unfold subtasks"),
+		),
+		EditNotes: key.NewBinding(
+			bindKeys(config["EditNotes"]),
+			key.WithHelp(config["EditNotes"], "edit notes"),
+		),
+		SetRecurrence: key.NewBinding(
+			bindKeys(config["SetRecurrence"]),
+			key.WithHelp(config["SetRecurrence"], "set recurrence"),
+		),
+		Undo: key.NewBinding(
+			bindKeys(config["Undo"]),
+			key.WithHelp(config["Undo"], "undo"),
+		),
+		Redo: key.NewBinding(
+			bindKeys(config["Redo"]),
+			key.WithHelp(config["Redo"], "redo"),
+		),
+		Filter: key.NewBinding(
+			bindKeys(config["Filter"]),
+			key.WithHelp(config["Filter"], "filter"),
+		),
+		Search: key.NewBinding(
+			bindKeys(config["Search"]),
+			key.WithHelp(config["Search"], "search"),
+		),
+		SearchNext: key.NewBinding(
+			bindKeys(config["SearchNext"]),
+			key.WithHelp(config["SearchNext"], "next match"),
+		),
+		SearchPrev: key.NewBinding(
+			bindKeys(config["SearchPrev"]),
+			key.WithHelp(config["SearchPrev"], "previous match"),
+		),
 	}, nil
 }
 
@@ -251,14 +349,14 @@ func buildInsertKmapFromConfig(config map[string]string) (InsertKeyMap, error) {
 
 	return InsertKeyMap{
 		Discard: key.NewBinding(
-			key.WithKeys(config["Discard"]),
+			bindKeys(config["Discard"]),
 			key.WithHelp(config["Discard"], "discard changes"),
 		),
 		QuitNoWarning: key.NewBinding(
-			key.WithKeys(config["QuitNoWarning"]),
+			bindKeys(config["QuitNoWarning"]),
 		),
 		Save: key.NewBinding(
-			key.WithKeys(config["Save"]),
+			bindKeys(config["Save"]),
 			key.WithHelp(config["Save"], "save"),
 		),
 	}, nil
@@ -271,6 +369,7 @@ var DefaultInsertKeyMap, _ = buildInsertKmapFromConfig(mergeKeys(DefaultKeyMapCo
 var visualCommands = []string{
 	"Up", "UpFive", "Down", "DownFive", "NormalMode", "QuitNoWarning",
 	"Delete", "Yank", "ToggleCompletion", "JumpUp", "JumpDown",
+	"RaisePriority", "LowerPriority",
 }
 
 type VisualKeyMap struct {
@@ -285,6 +384,8 @@ type VisualKeyMap struct {
 	ToggleCompletion key.Binding
 	JumpUp           key.Binding
 	JumpDown         key.Binding
+	RaisePriority    key.Binding
+	LowerPriority    key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
@@ -301,6 +402,7 @@ func (k VisualKeyMap) FullHelp() [][]key.Binding {
 		{k.Down, k.NormalMode}, // second column
 		{k.Delete, k.QuitNoWarning},
 		{k.JumpUp, k.JumpDown},
+		{k.RaisePriority, k.LowerPriority},
 	}
 }
 
@@ -316,48 +418,56 @@ func buildVisualKmapFromConfig(config map[string]string) (VisualKeyMap, error) {
 
 	return VisualKeyMap{
 		Up: key.NewBinding(
-			key.WithKeys(config["Up"]),
+			bindKeys(config["Up"]),
 			key.WithHelp(config["Up"], "up"),
 		),
 		Down: key.NewBinding(
-			key.WithKeys(config["Down"]),
+			bindKeys(config["Down"]),
 			key.WithHelp(config["Down"], "down"),
 		),
 		UpFive: key.NewBinding(
-			key.WithKeys(config["UpFive"]),
+			bindKeys(config["UpFive"]),
 			key.WithHelp(config["UpFive"], "up 5"),
 		),
 		DownFive: key.NewBinding(
-			key.WithKeys(config["DownFive"]),
+			bindKeys(config["DownFive"]),
 			key.WithHelp(config["DownFive"], "down 5"),
 		),
 		NormalMode: key.NewBinding(
-			key.WithKeys(config["NormalMode"]),
+			bindKeys(config["NormalMode"]),
 			key.WithHelp(config["NormalMode"], "normal mode"),
 		),
 		QuitNoWarning: key.NewBinding(
-			key.WithKeys(config["QuitNoWarning"]),
+			bindKeys(config["QuitNoWarning"]),
 		),
 		Delete: key.NewBinding(
-			key.WithKeys(config["Delete"]),
+			bindKeys(config["Delete"]),
 			key.WithHelp(config["Delete"], "cut"),
 		),
 		Yank: key.NewBinding(
-			key.WithKeys(config["Yank"]),
+			bindKeys(config["Yank"]),
 			key.WithHelp(config["Yank"], "yank"),
 		),
 		ToggleCompletion: key.NewBinding(
-			key.WithKeys(config["ToggleCompletion"]),
+			bindKeys(config["ToggleCompletion"]),
 			key.WithHelp(config["ToggleCompletion"], "mark done/not done"),
 		),
 		JumpUp: key.NewBinding(
-			key.WithKeys(config["JumpUp"]),
+			bindKeys(config["JumpUp"]),
 			key.WithHelp(config["JumpUp"], "jump up"),
 		),
 		JumpDown: key.NewBinding(
-			key.WithKeys(config["JumpDown"]),
+			bindKeys(config["JumpDown"]),
 			key.WithHelp(config["JumpDown"], "jump down"),
 		),
+		RaisePriority: key.NewBinding(
+			bindKeys(config["RaisePriority"]),
+			key.WithHelp(config["RaisePriority"], "raise priority"),
+		),
+		LowerPriority: key.NewBinding(
+			bindKeys(config["LowerPriority"]),
+			key.WithHelp(config["LowerPriority"], "lower priority"),
+		),
 	}, nil
 }
 
@@ -396,10 +506,10 @@ func LoadKmap(pth string) (KeyMap, error) {
 	insertKeys := mergeKeys(config["Shared"], config["Insert"])
 	visualKeys := mergeKeys(config["Shared"], config["Visual"])
 
-	// Merge mode with default to fill in missing keys
-	normalKeys = mergeKeys(DefaultKeyMapConfig["Normal"], normalKeys)
-	insertKeys = mergeKeys(DefaultKeyMapConfig["Insert"], insertKeys)
-	visualKeys = mergeKeys(DefaultKeyMapConfig["Visual"], visualKeys)
+	// Merge mode with default to fill in missing keys (including shared ones)
+	normalKeys = fillDefaultKeys(normalKeys, mergeKeys(DefaultKeyMapConfig["Shared"], DefaultKeyMapConfig["Normal"]))
+	insertKeys = fillDefaultKeys(insertKeys, mergeKeys(DefaultKeyMapConfig["Shared"], DefaultKeyMapConfig["Insert"]))
+	visualKeys = fillDefaultKeys(visualKeys, mergeKeys(DefaultKeyMapConfig["Shared"], DefaultKeyMapConfig["Visual"]))
 
 	// Check if any keys are overlapping within a mode
 	err = checkConflicts(normalKeys, "normal")
@@ -436,9 +546,43 @@ func LoadKmap(pth string) (KeyMap, error) {
 	}, nil
 }
 
+// Add the default binding of every command missing from the config. Defaults
+// whose key the config already uses for another command are left unbound, so
+// that key-maps written before a command was added keep working.
+func fillDefaultKeys(config map[string]string, defaults map[string]string) map[string]string {
+	used := make(map[string]bool)
+	for _, keyStr := range config {
+		used[keyStr] = true
+	}
+
+	out := mergeKeys(nil, config)
+	for cmd, keyStr := range defaults {
+		if _, ok := out[cmd]; ok {
+			continue
+		}
+		if used[keyStr] {
+			keyStr = ""
+		}
+		out[cmd] = keyStr
+	}
+	return out
+}
+
+// Bind a command to its key, disabling commands left unbound so that they
+// neither match any key nor show up in the help.
+func bindKeys(keyStr string) key.BindingOpt {
+	if keyStr == "" {
+		return key.WithDisabled()
+	}
+	return key.WithKeys(keyStr)
+}
+
 func checkConflicts(cfg map[string]string, mode string) error {
 	seen := make(map[string]string)
 	for cmd, keyStr := range cfg {
+		if keyStr == "" { // unbound
+			continue
+		}
 		if seen[keyStr] != "" {
 			return fmt.Errorf("conflicting key binding in %s mode: %s and %s both bound to '%s'.\n", mode, cmd, seen[keyStr], keyStr)
 		} else {
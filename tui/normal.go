@@ -27,6 +27,9 @@ func handleNormalInput(msg tea.Msg, m model) (model, tea.Cmd) {
 			}
 		}
 	case false:
+		numPending, numDone := countDisplayed(m)
+		numTasks := numPending + numDone
+
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
 			switch {
@@ -36,7 +39,7 @@ func handleNormalInput(msg tea.Msg, m model) (model, tea.Cmd) {
 				}
 
 			case key.Matches(msg, m.kmap.Normal.Down):
-				if m.cursor.row < len(m.data.list.Tasks)-1 {
+				if m.cursor.row < numTasks-1 {
 					m.cursor.row++
 				}
 
@@ -48,10 +51,10 @@ func handleNormalInput(msg tea.Msg, m model) (model, tea.Cmd) {
 				}
 
 			case key.Matches(msg, m.kmap.Normal.DownFive):
-				if m.cursor.row < len(m.data.list.Tasks)-6 {
+				if m.cursor.row < numTasks-6 {
 					m.cursor.row += 4
 				} else {
-					m.cursor.row = len(m.data.list.Tasks) - 1
+					m.cursor.row = max(0, numTasks-1)
 				}
 
 			case key.Matches(msg, m.kmap.Normal.QuitWithWarning):
@@ -67,35 +70,37 @@ func handleNormalInput(msg tea.Msg, m model) (model, tea.Cmd) {
 
 			case key.Matches(msg, m.kmap.Normal.NewTask):
 				m.editInfo.taskId = -1
-				m.editInfo.location = m.data.list.Info.NumPending
+				m.editInfo.location = numPending
 				m.mode = "insert"
 
 			case key.Matches(msg, m.kmap.Normal.NewBefore):
-				if m.cursor.row >= m.data.list.Info.NumPending {
+				if m.cursor.row >= numPending {
 					m.editInfo.taskId = -1
-					m.editInfo.location = m.data.list.Info.NumPending
+					m.editInfo.location = numPending
 					m.mode = "insert"
 				} else {
 					m.editInfo.taskId = -1
 					m.editInfo.location = m.cursor.row
+					m.editInfo.parentId = m.data.list.Tasks[getTaskId(m, m.cursor.row)].ParentId
 					m.mode = "insert"
 				}
 
 			case key.Matches(msg, m.kmap.Normal.NewAfter):
-				if m.cursor.row >= m.data.list.Info.NumPending {
+				if m.cursor.row >= numPending {
 					m.editInfo.taskId = -1
-					m.editInfo.location = m.data.list.Info.NumPending
+					m.editInfo.location = numPending
 					m.mode = "insert"
 				} else {
 					m.editInfo.taskId = -1
 					m.editInfo.location = m.cursor.row + 1
+					m.editInfo.parentId = m.data.list.Tasks[getTaskId(m, m.cursor.row)].ParentId
 					m.mode = "insert"
 				}
 
 			case key.Matches(msg, m.kmap.Normal.EditTask):
-				if m.data.list.Info.NumTasks < 1 {
+				if numTasks < 1 {
 					m.editInfo.taskId = -1
-					m.editInfo.location = m.data.list.Info.NumPending
+					m.editInfo.location = numPending
 					m.mode = "insert"
 				} else {
 					taskId := getTaskId(m, m.cursor.row)
@@ -106,9 +111,9 @@ func handleNormalInput(msg tea.Msg, m model) (model, tea.Cmd) {
 				}
 
 			case key.Matches(msg, m.kmap.Normal.ClearAndEdit):
-				if m.data.list.Info.NumTasks < 1 {
+				if numTasks < 1 {
 					m.editInfo.taskId = -1
-					m.editInfo.location = m.data.list.Info.NumPending
+					m.editInfo.location = numPending
 					m.mode = "insert"
 				} else {
 					taskId := getTaskId(m, m.cursor.row)
@@ -117,9 +122,10 @@ func handleNormalInput(msg tea.Msg, m model) (model, tea.Cmd) {
 				}
 
 			case key.Matches(msg, m.kmap.Normal.DeleteTask):
-				if len(m.data.list.Tasks) == 0 {
+				if numTasks == 0 {
 					return m, nil
 				}
+				m = recordChange(m)
 				taskId := getTaskId(m, m.cursor.row)
 				task := m.data.list.Tasks[getTaskId(m, m.cursor.row)]
 				m.editInfo.copyBuff = []core.Task{*task}
@@ -127,43 +133,54 @@ func handleNormalInput(msg tea.Msg, m model) (model, tea.Cmd) {
 				m.editInfo.dirty = true
 
 				// fix cursor position
-				m.cursor.row = max(0, min(m.cursor.row, m.data.list.Info.NumTasks-1))
+				m.cursor.row = max(0, min(m.cursor.row, numTasks-2))
 
 			case key.Matches(msg, m.kmap.Normal.ToggleCompletion):
-				if m.data.list.Info.NumTasks < 1 {
+				if numTasks < 1 {
 					break
 				}
 
 				// toggle completion
+				m = recordChange(m)
 				currTaskId := getTaskId(m, m.cursor.row)
-				currTask := m.data.list.Tasks[currTaskId]
-				currTask.Done = !currTask.Done
+				m.data.list.ToggleCompletion(currTaskId)
 				m.editInfo.dirty = true
 
-				// update cursor position and list info
-				if currTask.Done {
-					m.data.list.Info.NumPending -= 1
-					m.data.list.Info.NumDone += 1
+				// update cursor position
+				numPending, numDone = countDisplayed(m)
+				if m.data.list.Tasks[currTaskId].Done {
 					// keep cursor on last not done task
-					m.cursor.row = max(0, min(m.cursor.row, m.data.list.Info.NumPending-1))
-
+					m.cursor.row = max(0, min(m.cursor.row, numPending-1))
 				} else {
-					m.data.list.Info.NumPending += 1
-					m.data.list.Info.NumDone -= 1
 					// keep cursor on first done task
-					if m.cursor.row < m.data.list.Info.NumPending && m.data.list.Info.NumDone > 0 {
+					if m.cursor.row < numPending && numDone > 0 {
 						m.cursor.row++
 					}
 				}
 
+			case key.Matches(msg, m.kmap.Normal.RaisePriority):
+				m = changePriority(m, m.cursor.row, m.cursor.row, core.Priority.Raise)
+
+			case key.Matches(msg, m.kmap.Normal.LowerPriority):
+				m = changePriority(m, m.cursor.row, m.cursor.row, core.Priority.Lower)
+
+			case key.Matches(msg, m.kmap.Normal.SortByPriority):
+				if numTasks < 1 {
+					m.view.byPriority = !m.view.byPriority
+					break
+				}
+				taskId := getTaskId(m, m.cursor.row)
+				m.view.byPriority = !m.view.byPriority
+				m.cursor.row = getDisplayIdx(m, taskId)
+
 			case key.Matches(msg, m.kmap.Normal.EnableVisualMode):
-				if m.data.list.Info.NumTasks > 0 {
+				if numTasks > 0 {
 					m.cursor.selStart = m.cursor.row
 					m.mode = "visual"
 				}
 
 			case key.Matches(msg, m.kmap.Normal.Yank):
-				if m.data.list.Info.NumTasks > 0 {
+				if numTasks > 0 {
 					task := m.data.list.Tasks[getTaskId(m, m.cursor.row)]
 					m.editInfo.copyBuff = []core.Task{*task}
 				}
@@ -183,8 +200,88 @@ func handleNormalInput(msg tea.Msg, m model) (model, tea.Cmd) {
 				}
 				m.editInfo.dirty = false
 
+			case key.Matches(msg, m.kmap.Normal.Indent):
+				if numTasks < 1 {
+					break
+				}
+				taskId := getTaskId(m, m.cursor.row)
+				parentId := findPrevSibling(m, m.cursor.row)
+				if parentId == -1 {
+					break
+				}
+				m = recordChange(m)
+				if err := m.data.list.MoveUnder(taskId, parentId); err == nil {
+					delete(m.view.folded, parentId) // make sure the task stays visible
+					m.cursor.row = getDisplayIdx(m, taskId)
+					m.editInfo.dirty = true
+				}
+
+			case key.Matches(msg, m.kmap.Normal.Outdent):
+				if numTasks < 1 {
+					break
+				}
+				taskId := getTaskId(m, m.cursor.row)
+				if m.data.list.Tasks[taskId].ParentId == 0 {
+					break
+				}
+				m = recordChange(m)
+				if err := m.data.list.Outdent(taskId); err == nil {
+					m.cursor.row = getDisplayIdx(m, taskId)
+					m.editInfo.dirty = true
+				}
+
+			case key.Matches(msg, m.kmap.Normal.ToggleFold):
+				if numTasks < 1 {
+					break
+				}
+				taskId := getTaskId(m, m.cursor.row)
+				if _, folded := m.view.folded[taskId]; folded {
+					delete(m.view.folded, taskId)
+				} else if m.data.list.HasChildren(taskId) {
+					m.view.folded[taskId] = struct{}{}
+				}
+
+			case key.Matches(msg, m.kmap.Normal.Undo):
+				m = undo(m)
+
+			case key.Matches(msg, m.kmap.Normal.Redo):
+				m = redo(m)
+
+			case key.Matches(msg, m.kmap.Normal.FilterTag):
+				m = openPrompt(m, "tag", m.view.tag)
+
+			case key.Matches(msg, m.kmap.Normal.Filter):
+				value := ""
+				if m.view.filter != nil {
+					value = m.view.filter.String()
+				}
+				m = openPrompt(m, "filter", value)
+
+			case key.Matches(msg, m.kmap.Normal.Search):
+				m = openPrompt(m, "search", "")
+
+			case key.Matches(msg, m.kmap.Normal.SearchNext):
+				m = jumpToMatch(m, true)
+
+			case key.Matches(msg, m.kmap.Normal.SearchPrev):
+				m = jumpToMatch(m, false)
+
+			case key.Matches(msg, m.kmap.Normal.SetRecurrence):
+				if numTasks < 1 {
+					break
+				}
+				taskId := getTaskId(m, m.cursor.row)
+				m = openPrompt(m, "recur", m.data.list.Tasks[taskId].Recurrence.String())
+				m.prompt.taskId = taskId
+
+			case key.Matches(msg, m.kmap.Normal.EditNotes):
+				if numTasks < 1 {
+					break
+				}
+				return m, editNotes(m, getTaskId(m, m.cursor.row))
+
 			case key.Matches(msg, m.kmap.Normal.JumpUp):
-				lastNotDone := m.data.list.Info.NumPending - 1
+				lastNotDone := numPending - 1
 				c := m.cursor.row
 				if c == lastNotDone+1 {
 					m.cursor.row = lastNotDone
@@ -195,15 +292,15 @@ func handleNormalInput(msg tea.Msg, m model) (model, tea.Cmd) {
 				}
 
 			case key.Matches(msg, m.kmap.Normal.JumpDown):
-				lastNotDone := m.data.list.Info.NumPending - 1
+				lastNotDone := numPending - 1
 				c := m.cursor.row
 
 				if c < lastNotDone {
 					m.cursor.row = lastNotDone
 				} else if c == lastNotDone {
-					m.cursor.row = lastNotDone + 1
+					m.cursor.row = min(lastNotDone+1, numTasks-1)
 				} else {
-					m.cursor.row = len(m.data.list.TaskIds) - 1
+					m.cursor.row = max(0, numTasks-1)
 				}
 			}
 		}
@@ -217,31 +314,99 @@ func renderNormalView(m model) string {
 }
 
 func getTaskId(m model, displayIdx int) int {
-	done, notDone := core.SplitByCompletion(m.data.list)
+	done, notDone := splitForDisplay(m)
 	combined := append(notDone, done...)
 	return combined[displayIdx].Id
 }
 
+// find the id of the closest task displayed above the given index that has the
+// same parent as the task at the index, or -1 if there is none
+func findPrevSibling(m model, displayIdx int) int {
+	done, notDone := splitForDisplay(m)
+	combined := append(notDone, done...)
+	parentId := combined[displayIdx].ParentId
+	for i := displayIdx - 1; i >= 0; i-- {
+		if combined[i].ParentId == parentId {
+			return combined[i].Id
+		}
+		if combined[i].Id == parentId {
+			break
+		}
+	}
+	return -1
+}
+
+// find the display index of the task with the given id
+func getDisplayIdx(m model, taskId int) int {
+	done, notDone := splitForDisplay(m)
+	for i, task := range append(notDone, done...) {
+		if task.Id == taskId {
+			return i
+		}
+	}
+	return 0
+}
+
+// apply fn to the priority of every task displayed between start and end (inclusive),
+// keeping the cursor on the same task in case the display order changes
+func changePriority(m model, start, end int, fn func(core.Priority) core.Priority) model {
+	if numPending, numDone := countDisplayed(m); numPending+numDone < 1 {
+		return m
+	}
+	m = recordChange(m)
+	done, notDone := splitForDisplay(m)
+	combined := append(notDone, done...)
+	cursorTaskId := combined[m.cursor.row].Id
+	for i := start; i <= end; i++ {
+		task := combined[i]
+		m.data.list.SetPriority(task.Id, fn(task.Priority))
+	}
+	m.cursor.row = getDisplayIdx(m, cursorTaskId)
+	m.editInfo.dirty = true
+	return m
+}
+
 func pasteTasks(m model, before bool) model {
 	if len(m.editInfo.copyBuff) == 0 {
 		return m
 	}
+	m = recordChange(m)
+
+	// find where to paste the tasks and which parent they are pasted under
+	taskIndex := 0
+	parentId := 0
+	numPending, numDone := countDisplayed(m)
+	if numPending+numDone > 0 {
+		displayIdx := min(m.cursor.row, numPending, numPending+numDone-1)
+		taskIndex = getTaskIndex(m, displayIdx)
+		parentId = m.data.list.Tasks[getTaskId(m, displayIdx)].ParentId
+	}
 
-	// create new tasks with unique id's
+	// create copies of the tasks with unique id's and UUIDs, keeping subtasks that
+	// were copied along with their parent nested under the copy of the parent
 	newTasks := make([]core.Task, len(m.editInfo.copyBuff))
+	newIds := make(map[int]int)
 	for i, task := range m.editInfo.copyBuff {
 		t, err := m.data.list.NewTask(task.Description, task.Done)
 		if err != nil {
 			panic(err)
 		}
-		newTasks[i] = t
+		newIds[task.Id] = t.Id
+		task.Id = t.Id
+		task.UUID = t.UUID
+		task.CreatedAt = t.CreatedAt
+		task.UpdatedAt = t.UpdatedAt
+		task.CompletedAt = t.CompletedAt
+		task.NextId = 0 // the copy did not spawn the original's next occurrence
+		if newParentId, ok := newIds[task.ParentId]; ok {
+			task.ParentId = newParentId
+		} else {
+			task.ParentId = parentId
+		}
+		newTasks[i] = task
 	}
 
 	// add new tasks to the list
-	taskIndex := 0
-	if m.data.list.Info.NumTasks-1 > 0 {
-		taskIndex = getTaskIndex(m, min(m.cursor.row, m.data.list.Info.NumPending))
-	}
 	for i, task := range newTasks {
 		pasteIdx := min(taskIndex+i+1, len(m.data.list.TaskIds))
 		if before {
@@ -251,6 +416,7 @@ func pasteTasks(m model, before bool) model {
 		if err != nil {
 			panic(err)
 		}
+		m.data.list.SetParent(task.Id, task.ParentId) // update completion of the parent
 	}
 
 	// fix cursor
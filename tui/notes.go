@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sent once the external editor used to edit a task's notes exits
+type notesEditedMsg struct {
+	taskId int
+	path   string // temporary file holding the notes
+	err    error
+}
+
+// the command used to edit notes: $VISUAL, then $EDITOR, then vi
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// write the notes of the task to a temporary file and open it in the user's editor
+func editNotes(m model, taskId int) tea.Cmd {
+	file, err := os.CreateTemp("", "listly-notes-*.md")
+	if err != nil {
+		return func() tea.Msg { return notesEditedMsg{taskId: taskId, err: err} }
+	}
+	path := file.Name()
+	_, err = file.WriteString(m.data.list.Tasks[taskId].Notes)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return notesEditedMsg{taskId: taskId, err: err} }
+	}
+
+	editor := editorCommand()
+	c := exec.Command(editor[0], append(editor[1:], path)...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return notesEditedMsg{taskId: taskId, path: path, err: err}
+	})
+}
+
+// read the edited notes back into the task
+func handleNotesEdited(msg notesEditedMsg, m model) model {
+	if msg.path != "" {
+		defer os.Remove(msg.path)
+	}
+	if msg.err != nil {
+		m.status = fmt.Sprintf("could not edit notes: %v", msg.err)
+		return m
+	}
+	content, err := os.ReadFile(msg.path)
+	if err != nil {
+		m.status = fmt.Sprintf("could not read notes: %v", err)
+		return m
+	}
+	task, ok := m.data.list.Tasks[msg.taskId]
+	if !ok {
+		return m
+	}
+	notes := strings.TrimRight(string(content), "\n")
+	if notes != task.Notes {
+		m = recordChange(m)
+		m.data.list.SetNotes(msg.taskId, notes)
+		m.editInfo.dirty = true
+	}
+	return m
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	key "github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jlz22/listly/core"
+)
+
+var promptLabels = map[string]string{
+	"tag":    "  Filter by tag (empty to clear): ",
+	"recur":  "  Repeat (e.g. daily, weekly on mon,thu, every 3 days - empty to clear): ",
+	"search": "  /",
+	"filter": "  Filter (e.g. pending and tag:backend and due<7d - empty to clear): ",
+}
+
+func handlePromptInput(msg tea.Msg, m model) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.kmap.Insert.Discard):
+			return promptToNormal(m), nil
+		case key.Matches(msg, m.kmap.Insert.QuitNoWarning):
+			return m, tea.Quit
+		case key.Matches(msg, m.kmap.Insert.Save):
+			m = applyPrompt(m, strings.TrimSpace(m.prompt.input.Value()))
+			return promptToNormal(m), nil
+		}
+	}
+
+	updatedInput, cmd := m.prompt.input.Update(msg)
+	m.prompt.input = updatedInput
+	return m, cmd
+}
+
+func renderPromptView(m model) string {
+	lines := buildLines(m, true)
+	return m.prompt.input.View() + "\n" + strings.Join(lines, "")
+}
+
+// switch to prompt mode to ask for the given kind of value, pre-filled with value
+func openPrompt(m model, kind string, value string) model {
+	m.prompt.kind = kind
+	m.prompt.input.Prompt = promptLabels[kind]
+	m.prompt.input.SetValue(value)
+	m.prompt.input.CursorEnd()
+	m.mode = "prompt"
+	return m
+}
+
+// act on the value entered into the prompt
+func applyPrompt(m model, value string) model {
+	switch m.prompt.kind {
+	case "tag":
+		m.view.tag = value
+		m.cursor.row = 0
+	case "search":
+		m = startSearch(m, value)
+	case "filter":
+		if value == "" {
+			m.view.filter = nil
+			m.cursor.row = 0
+			break
+		}
+		filter, err := core.ParseFilter(value, time.Now())
+		if err != nil {
+			m.status = strings.SplitN(err.Error(), "\n", 2)[0]
+			break
+		}
+		m.view.filter = filter
+		m.cursor.row = 0
+	case "recur":
+		recurrence, err := core.ParseRecurrence(value)
+		if err != nil {
+			m.status = err.Error()
+			break
+		}
+		if task, ok := m.data.list.Tasks[m.prompt.taskId]; ok && task.Recurrence.String() != recurrence.String() {
+			m = recordChange(m)
+			m.data.list.SetRecurrence(task.Id, recurrence)
+			m.editInfo.dirty = true
+		}
+	}
+	return m
+}
+
+func promptToNormal(m model) model {
+	m.prompt.input.Reset()
+	m.prompt.kind = ""
+	m.prompt.taskId = 0
+	m.mode = "normal"
+	return m
+}
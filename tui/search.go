@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jlz22/listly/core"
+)
+
+// the last search made in the open list
+type search struct {
+	query   string
+	pattern *regexp.Regexp
+}
+
+// Search the displayed tasks for the query and move the cursor to the next match.
+// Like Vim's smartcase, case is ignored unless the query has upper case letters.
+func startSearch(m model, query string) model {
+	if query == "" {
+		return m
+	}
+	opts := core.SearchOptions{CaseSensitive: strings.ToLower(query) != query}
+	pattern, err := core.CompileSearch(query, opts)
+	if err != nil {
+		m.status = err.Error()
+		return m
+	}
+	m.search = search{query: query, pattern: pattern}
+	return jumpToMatch(m, true)
+}
+
+// Move the cursor to the next (or previous) displayed task that matches the last
+// search, wrapping around at the end (or start) of the list.
+func jumpToMatch(m model, forward bool) model {
+	if m.search.pattern == nil {
+		m.status = "No previous search"
+		return m
+	}
+
+	done, notDone := splitForDisplay(m)
+	combined := append(notDone, done...)
+	matches := []int{}
+	for i, task := range combined {
+		if task.Matches(m.search.pattern) {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		m.status = fmt.Sprintf("Pattern not found: %s", m.search.query)
+		return m
+	}
+
+	next := -1
+	if forward {
+		next = 0 // wrap around to the first match
+		for i, row := range matches {
+			if row > m.cursor.row {
+				next = i
+				break
+			}
+		}
+	} else {
+		next = len(matches) - 1 // wrap around to the last match
+		for i := len(matches) - 1; i >= 0; i-- {
+			if matches[i] < m.cursor.row {
+				next = i
+				break
+			}
+		}
+	}
+	m.cursor.row = matches[next]
+	m.status = fmt.Sprintf("/%s [%d/%d]", m.search.query, next+1, len(matches))
+	return m
+}
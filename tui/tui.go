@@ -1,7 +1,9 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	help "github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -32,6 +34,22 @@ type editInfo struct {
 	dirty     bool
 	taskId    int // the id of the task being edited
 	location  int // where to insert the new task
+	parentId  int // the parent of the new task
+}
+
+// options that change how tasks are displayed without changing the list itself
+type view struct {
+	byPriority bool             // sort pending tasks by priority
+	tag        string           // only show tasks carrying this tag
+	filter     *core.Filter     // only show tasks matching this filter
+	folded     map[int]struct{} // ids of tasks whose subtasks are hidden
+}
+
+// single line input used to ask the user for something other than a task description
+type prompt struct {
+	input  textinput.Model
+	kind   string // what is being asked for, e.g. "tag"
+	taskId int    // the task the value is for, if any
 }
 
 type model struct {
@@ -39,7 +57,12 @@ type model struct {
 	cursor       cursor
 	editInfo     editInfo
 	confirmation confirmation
+	view         view
+	prompt       prompt
+	history      history
+	search       search
 	mode         string
+	status       string // message shown above the help until the next key press
 	vp           viewport.Model
 	kmap         KeyMap
 }
@@ -50,6 +73,17 @@ var titleStyle = func() lipgloss.Style {
 	return lipgloss.NewStyle().BorderStyle(b).Padding(0, 0)
 }()
 
+var dateStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#8a8a8a"))
+
+var overdueStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5f5f")).Bold(true)
+
+var priorityStyles = map[core.Priority]lipgloss.Style{
+	core.PriorityLow:    lipgloss.NewStyle().Foreground(lipgloss.Color("#5fafff")),
+	core.PriorityMedium: lipgloss.NewStyle().Foreground(lipgloss.Color("#ffd75f")),
+	core.PriorityHigh:   lipgloss.NewStyle().Foreground(lipgloss.Color("#ff875f")),
+	core.PriorityUrgent: lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5f5f")).Bold(true),
+}
+
 func (m model) Init() tea.Cmd {
 	// No init I/O needed.
 	return nil
@@ -65,6 +99,12 @@ func NewModel(list core.List, kmap KeyMap) (model, error) {
 	ti.Width = 40
 	ti.Prompt = "    > [ ] "
 
+	// initialize prompt input
+	pi := textinput.New()
+	pi.Focus()
+	pi.CharLimit = 64
+	pi.Width = 40
+
 	return model{
 		data: data{
 			list: list,
@@ -84,6 +124,12 @@ func NewModel(list core.List, kmap KeyMap) (model, error) {
 			active:  false,
 			message: "",
 		},
+		view: view{
+			folded: make(map[int]struct{}),
+		},
+		prompt: prompt{
+			input: pi,
+		},
 		mode: "normal",
 		vp:   viewport.New(0, 0),
 		kmap: kmap,
@@ -110,7 +156,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		verticalHeight := headerHeight + footerHeight
 		m.vp.Width, m.vp.Height = msg.Width, msg.Height-verticalHeight
 
+	case notesEditedMsg:
+		m = handleNotesEdited(msg, m)
+
 	case tea.KeyMsg:
+		m.status = ""
 		switch m.mode {
 		case "normal":
 			m, cmd = handleNormalInput(msg, m)
@@ -118,6 +168,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m, cmd = handleInsertInput(msg, m)
 		case "visual":
 			m, cmd = handleVisualInput(msg, m)
+		case "prompt":
+			m, cmd = handlePromptInput(msg, m)
 		}
 	}
 
@@ -130,6 +182,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.vp.SetContent(renderInsertView(m) + "\nEOF")
 	case "visual":
 		m.vp.SetContent(renderVisualView(m) + "\nEOF")
+	case "prompt":
+		m.vp.SetContent(renderPromptView(m) + "\nEOF")
 	}
 
 	return m, cmd
@@ -139,7 +193,11 @@ func (m model) View() string {
 	if m.confirmation.active {
 		return "\n" + m.confirmation.message
 	}
-	out := makeHeader(m) + m.vp.View() + "\n\n"
+	out := makeHeader(m) + m.vp.View() + "\n"
+	if m.status != "" {
+		out += overdueStyle.Render("  " + m.status)
+	}
+	out += "\n"
 
 	switch m.mode {
 	case "normal":
@@ -148,27 +206,33 @@ func (m model) View() string {
 		return out + makeFooter(m, help.New().FullHelpView(DefaultInsertKeyMap.FullHelp()))
 	case "visual":
 		return out + makeFooter(m, help.New().FullHelpView(DefaultVisualKeyMap.FullHelp()))
+	case "prompt":
+		return out + makeFooter(m, help.New().FullHelpView(DefaultInsertKeyMap.FullHelp()))
 	}
 	return "Developer is a monkey - this shouldn't happen"
 }
 
 // convert the list into a bunch of lines
 func buildLines(m model, includeCursor bool) []string {
-	if m.data.list.Info.NumTasks == 0 {
-		return []string{"\n No tasks in this list. Press \"n\" to add one.\n\n"}
+	done, notDone := splitForDisplay(m)
+	if len(done)+len(notDone) == 0 {
+		if m.data.list.Info.NumTasks > 0 {
+			return []string{"\n No tasks match the current filter.\n\n"}
+		}
+		return []string{fmt.Sprintf("\n No tasks in this list. Press %q to add one.\n\n", m.kmap.Normal.NewTask.Help().Key)}
 	}
-	lines := make([]string, 0, 1+m.data.list.Info.NumTasks+1) // + 1 for the dividing bar
+	lines := make([]string, 0, 1+len(done)+len(notDone)+1) // + 1 for the dividing bar
 	lines = append(lines, "\n  Todo:\n\n")
 
 	// track the task count to know when to place the cursor
 	i := 0
+	now := time.Now()
 
 	// render incomplete tasks
-	done, notDone := core.SplitByCompletion(m.data.list)
 	for _, task := range notDone {
-		str := "      [ ] " + task.Description + "\n"
+		str := "      " + renderIndent(m, task) + "[ ] " + renderPriority(task) + task.Description + renderSubtasks(m, task) + renderNotes(task) + renderDates(task, now) + "\n"
 		if i == m.cursor.row && includeCursor {
-			str = "    > [ ] " + task.Description + "\n"
+			str = "    " + renderIndent(m, task) + "> [ ] " + renderPriority(task) + task.Description + renderSubtasks(m, task) + renderNotes(task) + renderDates(task, now) + "\n"
 		}
 		lines = append(lines, str)
 		i++
@@ -178,9 +242,9 @@ func buildLines(m model, includeCursor bool) []string {
 	if len(done) > 0 {
 		lines = append(lines, "\n  Complete:\n\n") // add a blank line between pending and completed tasks
 		for _, task := range done {
-			str := "      [x] " + task.Description + "\n"
+			str := "      " + renderIndent(m, task) + "[x] " + renderPriority(task) + task.Description + renderSubtasks(m, task) + renderNotes(task) + renderDates(task, now) + "\n"
 			if i == m.cursor.row && includeCursor {
-				str = "    > [x] " + task.Description + "\n"
+				str = "    " + renderIndent(m, task) + "> [x] " + renderPriority(task) + task.Description + renderSubtasks(m, task) + renderNotes(task) + renderDates(task, now) + "\n"
 			}
 			lines = append(lines, str)
 			i++
@@ -190,6 +254,99 @@ func buildLines(m model, includeCursor bool) []string {
 	return lines
 }
 
+// split the list into completed and pending tasks in the order they are displayed
+func splitForDisplay(m model) (done, notDone []*core.Task) {
+	done, notDone = core.SplitByCompletion(m.data.list)
+	if m.view.byPriority {
+		notDone = core.SortByPriority(notDone)
+	}
+	if m.view.tag != "" {
+		done = core.FilterByTag(done, m.view.tag)
+		notDone = core.FilterByTag(notDone, m.view.tag)
+	}
+	if m.view.filter != nil {
+		done = core.FilterTasks(done, m.view.filter)
+		notDone = core.FilterTasks(notDone, m.view.filter)
+	}
+	if len(m.view.folded) > 0 {
+		done = removeFolded(m, done)
+		notDone = removeFolded(m, notDone)
+	}
+	return done, notDone
+}
+
+// remove the tasks that are hidden because one of their ancestors is folded
+func removeFolded(m model, tasks []*core.Task) []*core.Task {
+	out := []*core.Task{}
+	for _, task := range tasks {
+		hidden := false
+		parent, ok := m.data.list.Tasks[task.ParentId]
+		for depth := 0; ok && depth <= len(m.data.list.Tasks); depth++ {
+			if _, folded := m.view.folded[parent.Id]; folded {
+				hidden = true
+				break
+			}
+			parent, ok = m.data.list.Tasks[parent.ParentId]
+		}
+		if !hidden {
+			out = append(out, task)
+		}
+	}
+	return out
+}
+
+// number of pending and completed tasks that are currently displayed
+func countDisplayed(m model) (numPending, numDone int) {
+	done, notDone := splitForDisplay(m)
+	return len(notDone), len(done)
+}
+
+// render the indentation that shows how deeply a task is nested
+func renderIndent(m model, task *core.Task) string {
+	return strings.Repeat("    ", m.data.list.Depth(task.Id))
+}
+
+// render the progress of a task's subtasks and whether they are folded
+func renderSubtasks(m model, task *core.Task) string {
+	done, total := m.data.list.Progress(task.Id)
+	if total == 0 {
+		return ""
+	}
+	out := fmt.Sprintf(" (%d/%d)", done, total)
+	if _, folded := m.view.folded[task.Id]; folded {
+		out += " ..."
+	}
+	return dateStyle.Render(out)
+}
+
+// mark tasks that have notes
+func renderNotes(task *core.Task) string {
+	if task.Notes == "" {
+		return ""
+	}
+	return dateStyle.Render(" [notes]")
+}
+
+// render the priority label that goes in front of a task's description
+func renderPriority(task *core.Task) string {
+	if task.Priority == core.PriorityNone {
+		return ""
+	}
+	return priorityStyles[task.Priority].Render("("+task.Priority.String()+")") + " "
+}
+
+// render the due and scheduled dates of a task, highlighting overdue tasks
+func renderDates(task *core.Task, now time.Time) string {
+	summary := task.DateSummary()
+	if summary == "" {
+		return ""
+	}
+	if task.IsOverdue(now) {
+		return "  " + overdueStyle.Render("("+summary+", overdue)")
+	}
+	return "  " + dateStyle.Render("("+summary+")")
+}
+
 func makeHeader(m model) string {
 	listName := m.data.list.Info.Name
 
@@ -197,6 +354,15 @@ func makeHeader(m model) string {
 	if m.editInfo.dirty {
 		listName += " (*)"
 	}
+	if m.view.byPriority {
+		listName += " [by priority]"
+	}
+	if m.view.tag != "" {
+		listName += " [tag: " + m.view.tag + "]"
+	}
+	if m.view.filter != nil {
+		listName += " [filter: " + m.view.filter.String() + "]"
+	}
 
 	title := titleStyle.Render(listName)
 	line := strings.Repeat("─", max(0, m.vp.Width-lipgloss.Width(title)))
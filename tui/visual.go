@@ -15,6 +15,9 @@ var visualHighlightStyle = lipgloss.NewStyle().
 	Bold(true)
 
 func handleVisualInput(msg tea.Msg, m model) (model, tea.Cmd) {
+	numPending, numDone := countDisplayed(m)
+	numTasks := numPending + numDone
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
@@ -24,7 +27,7 @@ func handleVisualInput(msg tea.Msg, m model) (model, tea.Cmd) {
 			}
 
 		case key.Matches(msg, m.kmap.Visual.Down):
-			if m.cursor.row < len(m.data.list.Tasks)-1 {
+			if m.cursor.row < numTasks-1 {
 				m.cursor.row++
 			}
 
@@ -36,10 +39,10 @@ func handleVisualInput(msg tea.Msg, m model) (model, tea.Cmd) {
 			}
 
 		case key.Matches(msg, m.kmap.Visual.DownFive):
-			if m.cursor.row < len(m.data.list.Tasks)-6 {
+			if m.cursor.row < numTasks-6 {
 				m.cursor.row += 4
 			} else {
-				m.cursor.row = len(m.data.list.Tasks) - 1
+				m.cursor.row = numTasks - 1
 			}
 
 		case key.Matches(msg, m.kmap.Visual.NormalMode):
@@ -53,10 +56,12 @@ func handleVisualInput(msg tea.Msg, m model) (model, tea.Cmd) {
 			m.editInfo.copyBuff = copyBuff
 
 			// delete the selection
+			m = recordChange(m)
+			m.editInfo.dirty = true
 			start := min(m.cursor.selStart, m.cursor.row)
 			end := max(m.cursor.selStart, m.cursor.row)
 			numToRemove := end - start
-			done, notDone := core.SplitByCompletion(m.data.list)
+			done, notDone := splitForDisplay(m)
 			combined := append(notDone, done...)
 			for i := 0; i <= numToRemove; i++ {
 				taskId := combined[start+i].Id
@@ -66,7 +71,7 @@ func handleVisualInput(msg tea.Msg, m model) (model, tea.Cmd) {
 				}
 			}
 			m = visualToNormal(m)
-			m.cursor.row = min(m.data.list.Info.NumTasks-1, m.cursor.row)
+			m.cursor.row = max(0, min(numTasks-numToRemove-2, m.cursor.row))
 
 		case key.Matches(msg, m.kmap.Visual.Yank):
 			copyBuff := copySelection(m)
@@ -76,25 +81,39 @@ func handleVisualInput(msg tea.Msg, m model) (model, tea.Cmd) {
 		case key.Matches(msg, m.kmap.Visual.ToggleCompletion):
 			start := min(m.cursor.selStart, m.cursor.row)
 			end := max(m.cursor.selStart, m.cursor.row)
-			done, notDone := core.SplitByCompletion(m.data.list)
+			done, notDone := splitForDisplay(m)
 			combined := append(notDone, done...)
+			// toggling a parent also toggles its subtasks, so only toggle tasks
+			// that have not already been changed by a selected parent
+			m = recordChange(m)
+			m.editInfo.dirty = true
+			wasDone := make([]bool, len(combined))
+			for i := start; i <= end; i++ {
+				wasDone[i] = combined[i].Done
+			}
 			for i := start; i <= end; i++ {
-				task := combined[i]
-				task.Done = !task.Done
-				if task.Done {
-					m.data.list.Info.NumDone++
-					m.data.list.Info.NumPending--
-				} else {
-					m.data.list.Info.NumDone--
-					m.data.list.Info.NumPending++
+				if combined[i].Done == wasDone[i] {
+					m.data.list.ToggleCompletion(combined[i].Id)
 				}
 				m.cursor.row--
 			}
 			m.cursor.row = max(0, m.cursor.row)
 			m = visualToNormal(m)
 
+		case key.Matches(msg, m.kmap.Visual.RaisePriority):
+			start := min(m.cursor.selStart, m.cursor.row)
+			end := max(m.cursor.selStart, m.cursor.row)
+			m = changePriority(m, start, end, core.Priority.Raise)
+			m = visualToNormal(m)
+
+		case key.Matches(msg, m.kmap.Visual.LowerPriority):
+			start := min(m.cursor.selStart, m.cursor.row)
+			end := max(m.cursor.selStart, m.cursor.row)
+			m = changePriority(m, start, end, core.Priority.Lower)
+			m = visualToNormal(m)
+
 		case key.Matches(msg, DefaultNormalKeyMap.JumpUp):
-			lastNotDone := m.data.list.Info.NumPending - 1
+			lastNotDone := numPending - 1
 			c := m.cursor.row
 			if c == lastNotDone+1 {
 				m.cursor.row = lastNotDone
@@ -105,15 +124,15 @@ func handleVisualInput(msg tea.Msg, m model) (model, tea.Cmd) {
 			}
 
 		case key.Matches(msg, DefaultNormalKeyMap.JumpDown):
-			lastNotDone := m.data.list.Info.NumPending - 1
+			lastNotDone := numPending - 1
 			c := m.cursor.row
 
 			if c < lastNotDone {
 				m.cursor.row = lastNotDone
 			} else if c == lastNotDone {
-				m.cursor.row = lastNotDone + 1
+				m.cursor.row = min(lastNotDone+1, numTasks-1)
 			} else {
-				m.cursor.row = len(m.data.list.TaskIds) - 1
+				m.cursor.row = numTasks - 1
 			}
 		}
 	}
@@ -124,7 +143,8 @@ func handleVisualInput(msg tea.Msg, m model) (model, tea.Cmd) {
 func renderVisualView(m model) string {
 	lines := buildLines(m, true)
 
-	sepBarIdx := m.data.list.Info.NumPending + 1
+	numPending, _ := countDisplayed(m)
+	sepBarIdx := numPending + 1
 	start := min(m.cursor.selStart, m.cursor.row)
 	end := max(m.cursor.selStart, m.cursor.row)
 	numSelected := end - start
@@ -153,7 +173,7 @@ func copySelection(m model) []core.Task {
 	end := max(m.cursor.selStart, m.cursor.row)
 	copyBuff := make([]core.Task, end-start+1)
 
-	done, notDone := core.SplitByCompletion(m.data.list)
+	done, notDone := splitForDisplay(m)
 	combined := append(notDone, done...)
 
 	// fill buff